@@ -0,0 +1,23 @@
+package errs_test
+
+import (
+	"testing"
+
+	"github.com/thirathawat/errs"
+)
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errs.New(errs.CodeNotFound, "User not found")
+	}
+}
+
+func BenchmarkNewWithInfo(b *testing.B) {
+	info := map[string]interface{}{"userId": 42}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errs.New(errs.CodeNotFound, "User not found", errs.WithInfo(info))
+	}
+}
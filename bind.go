@@ -0,0 +1,67 @@
+package errs
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// BindJSON binds the JSON body of the request into obj. On failure it writes
+// the error with ResponseError and InvalidStructError and returns false.
+//
+//	var req createUserRequest
+//	if !errs.BindJSON(c, &req) {
+//		return
+//	}
+func BindJSON(c *gin.Context, obj interface{}) bool {
+	return bind(c, c.ShouldBindJSON(obj))
+}
+
+// BindQuery binds the query string of the request into obj like BindJSON.
+func BindQuery(c *gin.Context, obj interface{}) bool {
+	return bind(c, c.ShouldBindQuery(obj))
+}
+
+// BindURI binds the path parameters of the request into obj like BindJSON.
+func BindURI(c *gin.Context, obj interface{}) bool {
+	return bind(c, c.ShouldBindUri(obj))
+}
+
+// bind writes the error of a binding, if any, and reports whether the
+// binding succeeded.
+func bind(c *gin.Context, err error) bool {
+	if err != nil {
+		ResponseError(c, InvalidStructError(err))
+		return false
+	}
+
+	return true
+}
+
+// RegisterValidation registers a custom validation tag end-to-end: fn is
+// registered on the validator of gin's binding, binding.Validator.Engine(),
+// so the tag can be used in binding struct tags, and message is registered
+// like RegisterValidationMessage, so InvalidStructError and the Bind
+// functions report the failures with it. The message is not registered if
+// the validator registration fails. It returns an error if the binding
+// validator is not a *validator.Validate, such as a custom
+// binding.StructValidator.
+//
+//	err := errs.RegisterValidation("sku", validateSKU, func(e validator.FieldError) string {
+//		return fmt.Sprintf("%s must be a valid SKU", e.Field())
+//	})
+func RegisterValidation(tag string, fn validator.Func, message func(validator.FieldError) string) error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("errs: binding validator %T is not a *validator.Validate", binding.Validator.Engine())
+	}
+
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+
+	RegisterValidationMessage(tag, message)
+	return nil
+}
@@ -0,0 +1,177 @@
+package errs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestBind(t *testing.T) {
+	type body struct {
+		Name string `json:"name" binding:"required"`
+	}
+	type query struct {
+		Page int `form:"page" binding:"min=1"`
+	}
+	type uri struct {
+		ID string `uri:"id" binding:"uuid"`
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", func(c *gin.Context) {
+		var b body
+		if !errs.BindJSON(c, &b) {
+			return
+		}
+		c.JSON(http.StatusOK, b)
+	})
+	router.GET("/users", func(c *gin.Context) {
+		var q query
+		if !errs.BindQuery(c, &q) {
+			return
+		}
+		c.JSON(http.StatusOK, q)
+	})
+	router.GET("/users/:id", func(c *gin.Context) {
+		var u uri
+		if !errs.BindURI(c, &u) {
+			return
+		}
+		c.JSON(http.StatusOK, u)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		info   map[string]interface{}
+	}{
+		{"json ok", http.MethodPost, "/users", `{"name":"Alice"}`, http.StatusOK, nil},
+		{"json invalid", http.MethodPost, "/users", `{}`, http.StatusBadRequest, map[string]interface{}{"name": "name is required"}},
+		{"query ok", http.MethodGet, "/users?page=2", "", http.StatusOK, nil},
+		{"query invalid", http.MethodGet, "/users?page=0", "", http.StatusBadRequest, map[string]interface{}{"page": "page must be at least 1"}},
+		{"uri ok", http.MethodGet, "/users/6f1c1a5e-0b8e-4f4e-9a39-3b1f0c2d4e5a", "", http.StatusOK, nil},
+		{"uri invalid", http.MethodGet, "/users/42", "", http.StatusBadRequest, map[string]interface{}{"id": "id is not valid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(router, tt.method, tt.path, bytes.NewBufferString(tt.body))
+			assert.Equal(t, tt.status, w.Code)
+
+			if tt.info != nil {
+				var got struct {
+					Code errs.Code              `json:"code"`
+					Info map[string]interface{} `json:"info"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, errs.CodeBadRequest, got.Code)
+				assert.Equal(t, tt.info, got.Info)
+			}
+		})
+	}
+}
+
+func TestBindJSONParseErrors(t *testing.T) {
+	type address struct {
+		Zip string `json:"zip"`
+	}
+	type body struct {
+		Name    string   `json:"name" binding:"required"`
+		Age     int      `json:"age"`
+		Tags    []string `json:"tags"`
+		Address address  `json:"address"`
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", func(c *gin.Context) {
+		var b body
+		if !errs.BindJSON(c, &b) {
+			return
+		}
+		c.JSON(http.StatusOK, b)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		info   map[string]interface{}
+		fields []errs.FieldError
+	}{
+		{"malformed", `{"name":`, map[string]interface{}{"error": "malformed or empty JSON body"}, nil},
+		{"syntax", `{"name" "Alice"}`, map[string]interface{}{"error": "malformed or empty JSON body"}, nil},
+		{"empty", ``, map[string]interface{}{"error": "malformed or empty JSON body"}, nil},
+		{"number", `{"name":"Alice","age":"ten"}`, map[string]interface{}{"age": "age must be a number"},
+			[]errs.FieldError{{Field: "age", Tag: "type", Param: "number", Message: "age must be a number"}}},
+		{"array", `{"name":"Alice","tags":"admin"}`, map[string]interface{}{"tags": "tags must be an array"},
+			[]errs.FieldError{{Field: "tags", Tag: "type", Param: "array", Message: "tags must be an array"}}},
+		{"nested", `{"name":"Alice","address":{"zip":10110}}`, map[string]interface{}{"address.zip": "address.zip must be a string"},
+			[]errs.FieldError{{Field: "address.zip", Tag: "type", Param: "string", Message: "address.zip must be a string"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(router, http.MethodPost, "/users", bytes.NewBufferString(tt.body))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var got struct {
+				Info   map[string]interface{} `json:"info"`
+				Fields []errs.FieldError      `json:"fields"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.info, got.Info)
+			assert.Equal(t, tt.fields, got.Fields)
+		})
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	type body struct {
+		Sku string `json:"sku" binding:"required,sku"`
+	}
+
+	err := errs.RegisterValidation("sku", func(fl validator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "SKU-")
+	}, func(validator.FieldError) string {
+		return "sku must start with SKU-"
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() { errs.RegisterValidationMessage("sku", nil) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/products", func(c *gin.Context) {
+		var b body
+		if !errs.BindJSON(c, &b) {
+			return
+		}
+		c.JSON(http.StatusOK, b)
+	})
+
+	w := performRequest(router, http.MethodPost, "/products", bytes.NewBufferString(`{"sku":"SKU-42"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/products", bytes.NewBufferString(`{"sku":"42"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var got struct {
+		Info   map[string]interface{} `json:"info"`
+		Fields []errs.FieldError      `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, map[string]interface{}{"sku": "sku must start with SKU-"}, got.Info)
+	assert.Equal(t, []errs.FieldError{{Field: "sku", Tag: "sku", Message: "sku must start with SKU-"}}, got.Fields)
+
+	assert.Error(t, errs.RegisterValidation("", func(validator.FieldLevel) bool { return true }, nil))
+}
@@ -0,0 +1,53 @@
+package errs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// CheckResponse returns nil if the response has a 2xx status code, and an
+// *Error otherwise. The error is decoded from the body when it is an errs
+// JSON response, or derived from the status code when it is not. A decoded
+// code unknown to the client, like a status without a code, keeps the status
+// code of the response. The body
+// is not read on success, and is replaced with the bytes read on failure so
+// it can still be read by the caller.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return New(CodeFromStatus(resp.StatusCode), "", WithCause(err), withStatus(resp.StatusCode))
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+		body = b
+	}
+
+	var e Error
+	if err := json.Unmarshal(body, &e); err == nil && e.Code != "" {
+		if !e.Code.Valid() {
+			e.status = resp.StatusCode
+		}
+
+		e.Severity = defaultSeverity(e.HTTPStatusCode())
+		return &e
+	}
+
+	return New(CodeFromStatus(resp.StatusCode), http.StatusText(resp.StatusCode), withStatus(resp.StatusCode))
+}
+
+// withStatus keeps the status code of the response, so statuses without a
+// code, such as 405, are not reported as a 500.
+func withStatus(status int) Option {
+	return func(o *option) {
+		o.status = status
+	}
+}
@@ -0,0 +1,136 @@
+package errs_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestCheckResponseSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.NoError(t, errs.CheckResponse(resp))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(body))
+}
+
+func TestCheckResponseWithErrsBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "User not found", errs.WithInfo(map[string]interface{}{
+			"userId": "1",
+		})))
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/users/1")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	e, ok := errs.CheckResponse(resp).(*errs.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, errs.CodeNotFound, e.Code)
+		assert.Equal(t, "User not found", e.Message)
+		assert.Equal(t, "1", e.Info["userId"])
+		assert.False(t, e.Timestamp.IsZero())
+		assert.Equal(t, http.StatusNotFound, e.HTTPStatusCode())
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "User not found")
+}
+
+func TestCheckResponseWithOpaqueBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<html>forbidden</html>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	e, ok := errs.CheckResponse(resp).(*errs.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, errs.CodeForbidden, e.Code)
+		assert.Equal(t, "Forbidden", e.Message)
+	}
+}
+
+func TestCheckResponseWithOpaqueBodyKeepsStatus(t *testing.T) {
+	for _, status := range []int{http.StatusMethodNotAllowed, http.StatusBadGateway} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "<html>error</html>", status)
+			}))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			e, ok := errs.CheckResponse(resp).(*errs.Error)
+			if assert.True(t, ok) {
+				assert.Equal(t, status, e.HTTPStatusCode())
+				assert.Equal(t, http.StatusText(status), e.Message)
+			}
+		})
+	}
+}
+
+func TestCheckResponseWithUnknownCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"code":"QUOTA_DEPLETED","message":"Quota depleted"}`))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	e, ok := errs.CheckResponse(resp).(*errs.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, errs.Code("QUOTA_DEPLETED"), e.Code)
+		assert.Equal(t, http.StatusPaymentRequired, e.HTTPStatusCode())
+		assert.Equal(t, errs.SeverityWarn, e.Severity)
+	}
+}
+
+type failingBody struct {
+	closed bool
+}
+
+func (b *failingBody) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
+func (b *failingBody) Close() error             { b.closed = true; return nil }
+
+func TestCheckResponseReadError(t *testing.T) {
+	body := &failingBody{}
+	err := errs.CheckResponse(&http.Response{StatusCode: http.StatusBadGateway, Body: body})
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.True(t, body.closed)
+	assert.Equal(t, http.StatusBadGateway, errs.StatusCode(err))
+
+	err = errs.CheckResponse(&http.Response{StatusCode: http.StatusMethodNotAllowed, Body: &failingBody{}})
+	assert.Equal(t, http.StatusMethodNotAllowed, errs.StatusCode(err))
+}
@@ -0,0 +1,331 @@
+package errs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// config holds the package-level settings.
+type config struct {
+	// codeHeader is the response header that carries the error code.
+	codeHeader string
+
+	// requestIDKey is the gin context key that holds the request ID.
+	requestIDKey string
+
+	// contextInfo extracts log fields from the context of an error.
+	contextInfo func(context.Context) map[string]interface{}
+
+	// utcTimestamps sets the default timestamps of errors in UTC.
+	utcTimestamps bool
+
+	// defaultStatus is the HTTP status code of unknown codes.
+	defaultStatus int
+
+	// clientErrorSeverity is the default severity of 4xx errors.
+	clientErrorSeverity Severity
+
+	// autoLog logs errors at or above autoLogThreshold without WithLogErr.
+	autoLog          bool
+	autoLogThreshold int
+
+	// fieldName converts the struct field names of validation errors.
+	fieldName func(string) string
+
+	// firstMessagePerField keeps only the most important message per field.
+	firstMessagePerField bool
+
+	// allMessagesPerField lists every message of a field failing several
+	// rules.
+	allMessagesPerField bool
+
+	// envelope is the shape of JSON responses, with the keys of the code and
+	// message.
+	envelope   EnvelopeStyle
+	codeKey    string
+	messageKey string
+
+	// exposeInfo includes the info and data in the response bodies.
+	exposeInfo bool
+
+	// devMode adds the cause chain to the info of the response bodies.
+	devMode bool
+
+	// docsURLTemplate is the documentation link of the errors, with the
+	// {code} placeholder.
+	docsURLTemplate string
+
+	// statusInBody includes the HTTP status code in the JSON body.
+	statusInBody bool
+
+	// fallbackMessage is the message written for unknown errors.
+	fallbackMessage string
+
+	// pretty indents the JSON error responses.
+	pretty bool
+
+	// contentType is the Content-Type of the JSON error responses.
+	contentType string
+}
+
+// defaultContentType is the Content-Type of the JSON error responses unless
+// set with SetResponseContentType.
+const defaultContentType = "application/json; charset=utf-8"
+
+var (
+	configMu sync.RWMutex
+	cfg      = config{
+		requestIDKey:        "requestID",
+		defaultStatus:       http.StatusInternalServerError,
+		clientErrorSeverity: SeverityWarn,
+		autoLogThreshold:    http.StatusInternalServerError,
+		codeKey:             "code",
+		messageKey:          "message",
+		exposeInfo:          true,
+		fallbackMessage:     http.StatusText(http.StatusInternalServerError),
+		contentType:         defaultContentType,
+	}
+)
+
+// currentConfig returns a snapshot of the package-level settings.
+func currentConfig() config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return cfg
+}
+
+// setConfig updates the package-level settings.
+func setConfig(fn func(*config)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	fn(&cfg)
+}
+
+// SetEmitCodeHeader sets the response header that ResponseError uses to emit
+// the error code, e.g. "X-Error-Code". An empty name disables the header,
+// which is the default.
+func SetEmitCodeHeader(name string) {
+	setConfig(func(c *config) {
+		c.codeHeader = name
+	})
+}
+
+// SetRequestIDKey sets the gin context key that ResponseError reads the
+// request ID from, "requestID" by default. The request ID is returned as
+// info.requestID and the X-Request-ID header. An empty key disables it.
+func SetRequestIDKey(key string) {
+	setConfig(func(c *config) {
+		c.requestIDKey = key
+	})
+}
+
+// SetDefaultStatus sets the HTTP status code returned by HTTPStatusCode for
+// codes that are neither built-in nor registered,
+// http.StatusInternalServerError by default. It returns an error and keeps
+// the current status if status is outside 100-599.
+func SetDefaultStatus(status int) error {
+	if status < 100 || status > 599 {
+		return fmt.Errorf("errs: invalid default status %d", status)
+	}
+
+	setConfig(func(c *config) {
+		c.defaultStatus = status
+	})
+
+	return nil
+}
+
+// SetUTCTimestamps sets whether New sets the timestamps of errors in UTC
+// rather than in the local time zone, which keeps timestamps consistent
+// across deployments. It is recommended and disabled by default for
+// compatibility. Timestamps set with WithTimestamp are kept as is.
+func SetUTCTimestamps(enabled bool) {
+	setConfig(func(c *config) {
+		c.utcTimestamps = enabled
+	})
+}
+
+// SetContextInfoExtractor sets the function that extracts values such as the
+// user or tenant ID from the context passed with WithContext. The extracted
+// values are added to the log fields, not to the error returned to clients.
+func SetContextInfoExtractor(fn func(context.Context) map[string]interface{}) {
+	setConfig(func(c *config) {
+		c.contextInfo = fn
+	})
+}
+
+// SetFieldNameTransformer sets the function that converts struct field names
+// in validation info keys and messages, e.g. strcase.ToSnake for snake_case
+// APIs. A nil fn restores the default strcase.ToLowerCamel.
+func SetFieldNameTransformer(fn func(string) string) {
+	setConfig(func(c *config) {
+		c.fieldName = fn
+	})
+}
+
+// SetFirstMessagePerField sets whether the validation info keeps only the
+// most important message when a field fails several rules: required first,
+// then type checks such as email or numeric, then everything else.
+func SetFirstMessagePerField(enabled bool) {
+	setConfig(func(c *config) {
+		c.firstMessagePerField = enabled
+	})
+}
+
+// SetAllMessagesPerField sets whether the validation info lists every
+// message when a field fails several rules. The value of such a field is a
+// []string in the order of the validation errors; fields failing a single
+// rule keep a string. SetFirstMessagePerField takes precedence when both are
+// enabled.
+func SetAllMessagesPerField(enabled bool) {
+	setConfig(func(c *config) {
+		c.allMessagesPerField = enabled
+	})
+}
+
+// SetClientErrorSeverity sets the default severity of errors with a 4xx
+// HTTP status code, SeverityWarn by default, e.g. SeverityInfo so client
+// errors do not trip alerts. Server errors are always SeverityError, and
+// WithSeverity overrides both.
+func SetClientErrorSeverity(severity Severity) {
+	setConfig(func(c *config) {
+		c.clientErrorSeverity = severity
+	})
+}
+
+// SetAutoLog sets whether New logs every error whose HTTP status code is at
+// or above the auto log threshold, even without WithLogErr. It is disabled
+// by default.
+func SetAutoLog(enabled bool) {
+	setConfig(func(c *config) {
+		c.autoLog = enabled
+	})
+}
+
+// SetAutoLogThreshold sets the lowest HTTP status code logged when auto
+// logging is enabled, http.StatusInternalServerError by default.
+func SetAutoLogThreshold(status int) {
+	setConfig(func(c *config) {
+		c.autoLogThreshold = status
+	})
+}
+
+// SetExposeInfo sets whether ResponseError and WriteError include the info
+// and data of errors in the response bodies. It is enabled by default;
+// disabling it in production keeps internals out of the responses, while
+// the info is still logged. The validation fields are always included.
+func SetExposeInfo(enabled bool) {
+	setConfig(func(c *config) {
+		c.exposeInfo = enabled
+	})
+}
+
+// SetDevMode sets whether the response bodies include the chain of causes
+// of errors as "causes" in the info, each with the code of *Error causes and
+// the message, so the whole error path is visible during development. The
+// causes are only included while SetExposeInfo is enabled. Keep it disabled
+// in production.
+func SetDevMode(enabled bool) {
+	setConfig(func(c *config) {
+		c.devMode = enabled
+	})
+}
+
+// SetDocsURLTemplate sets the documentation link of every error, returned
+// to clients as "docs", with the {code} placeholder replaced by the code of
+// the error, e.g. "https://docs.example.com/errors/{code}". Links set with
+// WithDocsURL take precedence. An empty template, the default, disables it.
+func SetDocsURLTemplate(tmpl string) {
+	setConfig(func(c *config) {
+		c.docsURLTemplate = tmpl
+	})
+}
+
+// SetStatusInBody sets whether the JSON body of an error includes its HTTP
+// status code as "status", for clients behind gateways that rewrite the
+// status line. It is disabled by default.
+func SetStatusInBody(enabled bool) {
+	setConfig(func(c *config) {
+		c.statusInBody = enabled
+	})
+}
+
+// SetEnvelope sets the shape of the JSON error responses written by
+// ResponseError and WriteError, EnvelopeFlat by default.
+func SetEnvelope(style EnvelopeStyle) {
+	setConfig(func(c *config) {
+		c.envelope = style
+	})
+}
+
+// SetEnvelopeKeys renames the code and message keys of the JSON error
+// responses, e.g. "error_code" and "error_message". An empty key restores
+// the default "code" or "message". It returns an error and keeps the current
+// keys if the keys are equal or collide with "info", "fields", "data",
+// "docs", "timestamp" or "status".
+func SetEnvelopeKeys(code, message string) error {
+	if code == "" {
+		code = "code"
+	}
+	if message == "" {
+		message = "message"
+	}
+
+	if code == message {
+		return fmt.Errorf("errs: code and message keys are both %q", code)
+	}
+
+	for _, key := range []string{code, message} {
+		if envelopeKeys[key] {
+			return fmt.Errorf("errs: envelope key %q is reserved", key)
+		}
+	}
+
+	setConfig(func(c *config) {
+		c.codeKey = code
+		c.messageKey = message
+	})
+
+	return nil
+}
+
+// SetResponseContentType sets the Content-Type of the JSON error responses
+// written by ResponseError and WriteError, "application/json; charset=utf-8"
+// by default. An empty content type restores the default.
+func SetResponseContentType(contentType string) {
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	setConfig(func(c *config) {
+		c.contentType = contentType
+	})
+}
+
+// SetPretty sets whether ResponseError and WriteError write indented JSON,
+// which is easier to read with curl during development. It only changes the
+// whitespace of the response and is disabled by default.
+func SetPretty(enabled bool) {
+	setConfig(func(c *config) {
+		c.pretty = enabled
+	})
+}
+
+// SetFallbackMessage sets the message written by ResponseError and
+// WriteError for errors that are not *Error values, "Internal Server Error"
+// by default. The {requestID} placeholder is replaced with the request ID,
+// e.g. "An unexpected error occurred, please contact support with ID
+// {requestID}". An empty message restores the default.
+func SetFallbackMessage(msg string) {
+	if msg == "" {
+		msg = http.StatusText(http.StatusInternalServerError)
+	}
+
+	setConfig(func(c *config) {
+		c.fallbackMessage = msg
+	})
+}
@@ -0,0 +1,56 @@
+package errs
+
+import "context"
+
+// requestIDKey and traceIDKey are the context keys of the request and trace
+// IDs.
+type (
+	requestIDKey struct{}
+	traceIDKey   struct{}
+)
+
+// ContextWithRequestID returns a copy of ctx that carries the request ID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextWithTraceID returns a copy of ctx that carries the trace ID.
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceIDFromContext returns the trace ID carried by ctx, if any.
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the error for a done context: Timeout (504) when its
+// deadline was exceeded and ClientClosedRequest (499) when it was canceled.
+// It returns nil if the context is not done.
+func FromContext(ctx context.Context) *Error {
+	return Classify(ctx.Err())
+}
+
+// Classify returns err as an *Error like MapError: errors that already wrap
+// an *Error are returned as is, context.DeadlineExceeded is classified as
+// CodeTimeout, context.Canceled as CodeClientClosedRequest, and any other
+// error as CodeInternalServerError unless a mapping matches it. It returns
+// nil if err is nil.
+func Classify(err error) *Error {
+	return MapError(err)
+}
@@ -0,0 +1,112 @@
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestFromContextDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	err := errs.FromContext(ctx)
+	assert.Equal(t, errs.CodeTimeout, err.Code)
+	assert.Equal(t, "Gateway Timeout", err.Message)
+	assert.Equal(t, http.StatusGatewayTimeout, err.HTTPStatusCode())
+}
+
+func TestFromContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := errs.FromContext(ctx)
+	assert.Equal(t, errs.CodeClientClosedRequest, err.Code)
+	assert.Equal(t, "Client Closed Request", err.Message)
+	assert.Equal(t, errs.StatusClientClosedRequest, err.HTTPStatusCode())
+}
+
+func TestFromContextNotDone(t *testing.T) {
+	assert.Nil(t, errs.FromContext(context.Background()))
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), errs.StatusClientClosedRequest},
+		{"errs", fmt.Errorf("query: %w", errs.NotFound), http.StatusNotFound},
+		{"other", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errs.Classify(tt.err).HTTPStatusCode())
+		})
+	}
+
+	assert.Nil(t, errs.Classify(nil))
+}
+
+func TestNewCtx(t *testing.T) {
+	ctx := errs.ContextWithRequestID(context.Background(), "req-123")
+	ctx = errs.ContextWithTraceID(ctx, "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	info := map[string]interface{}{"userId": 42}
+	err := errs.NewCtx(ctx, errs.CodeNotFound, "User not found", errs.WithInfo(info))
+	assert.Equal(t, map[string]interface{}{
+		"userId":    42,
+		"requestID": "req-123",
+		"traceId":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}, err.Info)
+	assert.Equal(t, map[string]interface{}{"userId": 42}, info, "the info passed is not modified")
+}
+
+func TestNewCtxKeepsExplicitInfo(t *testing.T) {
+	ctx := errs.ContextWithRequestID(context.Background(), "req-123")
+	err := errs.NewCtx(ctx, errs.CodeNotFound, "", errs.WithInfo(map[string]interface{}{"requestID": "explicit"}))
+	assert.Equal(t, "explicit", err.Info["requestID"])
+}
+
+func TestNewCtxWithoutValues(t *testing.T) {
+	err := errs.NewCtx(context.Background(), errs.CodeNotFound, "User not found")
+	assert.Nil(t, err.Info)
+}
+
+func TestNewCtxPassesContextToLogger(t *testing.T) {
+	type tenantKey struct{}
+
+	errs.SetContextInfoExtractor(func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{"tenant": ctx.Value(tenantKey{})}
+	})
+	t.Cleanup(func() { errs.SetContextInfoExtractor(nil) })
+
+	l := &contextLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	errs.NewCtx(ctx, errs.CodeInternalServerError, "", errs.WithLogErr(errors.New("db is down")))
+	assert.Equal(t, ctx, l.ctx)
+	assert.Equal(t, "acme", l.fields["tenant"])
+}
+
+type contextLogger struct {
+	ctx    context.Context
+	fields map[string]interface{}
+}
+
+func (l *contextLogger) Log(ctx context.Context, _ errs.Severity, _ string, fields map[string]interface{}) {
+	l.ctx = ctx
+	l.fields = fields
+}
@@ -0,0 +1,68 @@
+package errs
+
+import "encoding/json"
+
+// EnvelopeStyle is the shape of the JSON error responses.
+type EnvelopeStyle int
+
+const (
+	// EnvelopeFlat writes the error as the top-level object:
+	// {"code": "...", "message": "..."}.
+	EnvelopeFlat EnvelopeStyle = iota
+
+	// EnvelopeNested wraps the error in an "error" object:
+	// {"error": {"code": "...", "message": "..."}}.
+	EnvelopeNested
+)
+
+// envelopeKeys are the keys of the JSON error object that the code and
+// message keys cannot be renamed to.
+var envelopeKeys = map[string]bool{
+	"info":      true,
+	"fields":    true,
+	"data":      true,
+	"docs":      true,
+	"timestamp": true,
+	"status":    true,
+}
+
+// jsonBody returns the JSON response body for v, applying the envelope
+// settings to an *Error and to the fallback body of unknown errors. Other
+// values are returned as is.
+func jsonBody(v interface{}) interface{} {
+	c := currentConfig()
+	if c.envelope == EnvelopeFlat && c.codeKey == "code" && c.messageKey == "message" {
+		return v
+	}
+
+	var body map[string]json.RawMessage
+	switch v := v.(type) {
+	case *Error:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+
+		if err := json.Unmarshal(b, &body); err != nil {
+			return v
+		}
+	case fallback:
+		code, _ := json.Marshal(CodeInternalServerError)
+		msg, _ := json.Marshal(string(v))
+		body = map[string]json.RawMessage{"code": code, "message": msg}
+	default:
+		return v
+	}
+
+	code, msg := body["code"], body["message"]
+	delete(body, "code")
+	delete(body, "message")
+	body[c.codeKey] = code
+	body[c.messageKey] = msg
+
+	if c.envelope == EnvelopeNested {
+		return map[string]interface{}{"error": body}
+	}
+
+	return body
+}
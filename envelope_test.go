@@ -0,0 +1,103 @@
+package errs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestSetEnvelope(t *testing.T) {
+	t.Cleanup(func() {
+		errs.SetEnvelope(errs.EnvelopeFlat)
+		_ = errs.SetEnvelopeKeys("", "")
+	})
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := errs.New(errs.CodeNotFound, "User not found", errs.WithTimestamp(ts))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, err)
+	})
+
+	tests := []struct {
+		name    string
+		style   errs.EnvelopeStyle
+		code    string
+		message string
+		want    string
+	}{
+		{"flat", errs.EnvelopeFlat, "", "",
+			`{"code":"NOT_FOUND","message":"User not found","timestamp":"2023-06-01T12:00:00Z"}`},
+		{"nested", errs.EnvelopeNested, "", "",
+			`{"error":{"code":"NOT_FOUND","message":"User not found","timestamp":"2023-06-01T12:00:00Z"}}`},
+		{"flat renamed", errs.EnvelopeFlat, "error_code", "error_message",
+			`{"error_code":"NOT_FOUND","error_message":"User not found","timestamp":"2023-06-01T12:00:00Z"}`},
+		{"nested renamed", errs.EnvelopeNested, "type", "",
+			`{"error":{"type":"NOT_FOUND","message":"User not found","timestamp":"2023-06-01T12:00:00Z"}}`},
+		{"swapped", errs.EnvelopeFlat, "message", "detail",
+			`{"message":"NOT_FOUND","detail":"User not found","timestamp":"2023-06-01T12:00:00Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs.SetEnvelope(tt.style)
+			assert.NoError(t, errs.SetEnvelopeKeys(tt.code, tt.message))
+
+			w := performRequest(router, http.MethodGet, "/users/1", nil)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.JSONEq(t, tt.want, w.Body.String())
+
+			w = httptest.NewRecorder()
+			errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/users/1", nil), err)
+			assert.JSONEq(t, tt.want, w.Body.String())
+		})
+	}
+}
+
+func TestSetEnvelopeFallback(t *testing.T) {
+	t.Cleanup(func() {
+		errs.SetEnvelope(errs.EnvelopeFlat)
+		_ = errs.SetEnvelopeKeys("", "")
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/boom", func(c *gin.Context) {
+		errs.ResponseError(c, errors.New("boom"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/boom", nil)
+	assert.JSONEq(t, `"Internal Server Error"`, w.Body.String())
+
+	errs.SetEnvelope(errs.EnvelopeNested)
+	assert.NoError(t, errs.SetEnvelopeKeys("error_code", ""))
+
+	w = performRequest(router, http.MethodGet, "/boom", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":{"error_code":"INTERNAL_SERVER_ERROR","message":"Internal Server Error"}}`, w.Body.String())
+}
+
+func TestSetEnvelopeKeysCollision(t *testing.T) {
+	t.Cleanup(func() { _ = errs.SetEnvelopeKeys("", "") })
+
+	assert.EqualError(t, errs.SetEnvelopeKeys("info", ""), `errs: envelope key "info" is reserved`)
+	assert.Error(t, errs.SetEnvelopeKeys("", "timestamp"))
+	assert.Error(t, errs.SetEnvelopeKeys("fields", "msg"))
+	assert.EqualError(t, errs.SetEnvelopeKeys("error", "error"), `errs: code and message keys are both "error"`)
+	assert.Error(t, errs.SetEnvelopeKeys("message", ""))
+
+	assert.NoError(t, errs.SetEnvelopeKeys("error_code", ""))
+	assert.Error(t, errs.SetEnvelopeKeys("info", ""))
+
+	w := httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/", nil), errs.NotFound)
+	assert.Contains(t, w.Body.String(), `"error_code":"NOT_FOUND"`)
+}
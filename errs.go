@@ -2,30 +2,42 @@
 package errs
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
-	"github.com/iancoleman/strcase"
-	"github.com/sirupsen/logrus"
+	"unicode"
 )
 
 // Common errors.
 var (
-	BadRequest          = New(CodeBadRequest, http.StatusText(http.StatusBadRequest))
-	Unauthorized        = New(CodeUnauthorized, http.StatusText(http.StatusUnauthorized))
-	Forbidden           = New(CodeForbidden, http.StatusText(http.StatusForbidden))
-	NotFound            = New(CodeNotFound, http.StatusText(http.StatusNotFound))
-	Gone                = New(CodeGone, http.StatusText(http.StatusGone))
-	TooManyRequest      = New(CodeTooManyRequests, http.StatusText(http.StatusTooManyRequests))
-	InternalServerError = New(CodeInternalServerError, http.StatusText(http.StatusInternalServerError))
-	NotImplemented      = New(CodeNotImplemented, http.StatusText(http.StatusNotImplemented))
-	ServiceUnavailable  = New(CodeServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+	BadRequest                 = newSentinel(CodeBadRequest, http.StatusText(http.StatusBadRequest))
+	Unauthorized               = newSentinel(CodeUnauthorized, http.StatusText(http.StatusUnauthorized))
+	PaymentRequired            = newSentinel(CodePaymentRequired, http.StatusText(http.StatusPaymentRequired))
+	Forbidden                  = newSentinel(CodeForbidden, http.StatusText(http.StatusForbidden))
+	NotFound                   = newSentinel(CodeNotFound, http.StatusText(http.StatusNotFound))
+	Conflict                   = newSentinel(CodeConflict, http.StatusText(http.StatusConflict))
+	Gone                       = newSentinel(CodeGone, http.StatusText(http.StatusGone))
+	UnprocessableEntity        = newSentinel(CodeUnprocessableEntity, http.StatusText(http.StatusUnprocessableEntity))
+	ValidationFailed           = newSentinel(CodeValidationFailed, "")
+	TooManyRequest             = newSentinel(CodeTooManyRequests, http.StatusText(http.StatusTooManyRequests))
+	UnavailableForLegalReasons = newSentinel(CodeUnavailableForLegalReasons, http.StatusText(http.StatusUnavailableForLegalReasons))
+	InternalServerError        = newSentinel(CodeInternalServerError, http.StatusText(http.StatusInternalServerError))
+	NotImplemented             = newSentinel(CodeNotImplemented, http.StatusText(http.StatusNotImplemented))
+	ServiceUnavailable         = newSentinel(CodeServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+	Timeout                    = newSentinel(CodeTimeout, http.StatusText(http.StatusGatewayTimeout))
+	ClientClosedRequest        = newSentinel(CodeClientClosedRequest, "Client Closed Request")
 )
 
+// StatusClientClosedRequest is the non-standard HTTP status code used when
+// the client closes the connection before the server responds.
+const StatusClientClosedRequest = 499
+
 // Code represents an error code.
 type Code string
 
@@ -34,64 +46,410 @@ func (c Code) String() string {
 	return string(c)
 }
 
+// Title returns the code as a title cased label, e.g. "Too Many Requests"
+// for CodeTooManyRequests. Words are separated by underscores, hyphens or
+// spaces, so "NOT_FOUND_V2" becomes "Not Found V2".
+func (c Code) Title() string {
+	words := strings.FieldsFunc(c.String(), func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+
+	return strings.Join(words, " ")
+}
+
 // Error codes.
 const (
-	CodeBadRequest      Code = "BAD_REQUEST"
-	CodeUnauthorized    Code = "UNAUTHORIZED"
-	CodeForbidden       Code = "FORBIDDEN"
-	CodeNotFound        Code = "NOT_FOUND"
-	CodeGone            Code = "GONE"
-	CodeTooManyRequests Code = "TOO_MANY_REQUESTS"
+	CodeBadRequest                 Code = "BAD_REQUEST"
+	CodeUnauthorized               Code = "UNAUTHORIZED"
+	CodePaymentRequired            Code = "PAYMENT_REQUIRED"
+	CodeForbidden                  Code = "FORBIDDEN"
+	CodeNotFound                   Code = "NOT_FOUND"
+	CodeConflict                   Code = "CONFLICT"
+	CodeGone                       Code = "GONE"
+	CodeUnprocessableEntity        Code = "UNPROCESSABLE_ENTITY"
+	CodeValidationFailed           Code = "VALIDATION_FAILED"
+	CodeTooManyRequests            Code = "TOO_MANY_REQUESTS"
+	CodeUnavailableForLegalReasons Code = "UNAVAILABLE_FOR_LEGAL_REASONS"
+	CodeClientClosedRequest        Code = "CLIENT_CLOSED_REQUEST"
 
 	CodeInternalServerError Code = "INTERNAL_SERVER_ERROR"
 	CodeNotImplemented      Code = "NOT_IMPLEMENTED"
 	CodeServiceUnavailable  Code = "SERVICE_UNAVAILABLE"
+	CodeTimeout             Code = "TIMEOUT"
 )
 
 // Error represents an error.
 type Error struct {
 	// Code is the error code.
-	Code Code `json:"code"`
+	Code Code `json:"code" xml:"code"`
 
 	// Message is the error message.
-	Message string `json:"message"`
+	Message string `json:"message" xml:"message"`
+
+	// PublicMessage is the message returned to clients instead of Message,
+	// which is then only logged. It is ignored when empty.
+	PublicMessage string `json:"-" xml:"-"`
 
 	// Info is additional information about the error.
-	Info map[string]interface{} `json:"info,omitempty"`
+	Info map[string]interface{} `json:"info,omitempty" xml:"-"`
+
+	// Fields are the validation failures in the order reported by the
+	// validator, which follows the struct declaration order.
+	Fields []FieldError `json:"fields,omitempty" xml:"-"`
+
+	// Data is a typed, machine-readable payload, such as the remaining
+	// quota, kept apart from the free-form Info.
+	Data interface{} `json:"data,omitempty" xml:"-"`
+
+	// DocsURL links to the documentation of the error, set with WithDocsURL
+	// or derived from the template set with SetDocsURLTemplate.
+	DocsURL string `json:"docs,omitempty" xml:"docs,omitempty"`
 
 	// Timestamp is the time when the error occurred.
-	Timestamp time.Time `json:"timestamp"`
+	Timestamp time.Time `json:"timestamp" xml:"timestamp"`
+
+	// Severity is the level the error is logged at. It defaults to
+	// SeverityError for server errors and SeverityWarn otherwise, see
+	// SetClientErrorSeverity.
+	Severity Severity `json:"-" xml:"-"`
+
+	// cause is the underlying error, kept out of the response.
+	cause error
+
+	// headers are the response headers set with WithHeaders.
+	headers map[string]string
+
+	// lazy is the message of an error created with NewLazy.
+	lazy *lazyMessage
+
+	// lazyInfo is the info computed by the WithInfoFunc function.
+	lazyInfo *lazyInfo
+
+	// status is the HTTP status code of the response decoded by
+	// CheckResponse when the code is not known.
+	status int
+
+	// interpolate replaces the {key} placeholders of the message with info
+	// values, see WithInterpolation.
+	interpolate bool
+
+	// validationMessages are the validation messages of the info, kept
+	// unredacted when serialized.
+	validationMessages map[string]interface{}
+
+	// collapsed are the errors with the same code merged into this one by
+	// Wrap, matched by Is.
+	collapsed []*Error
+
+	// sentinel marks the package-level errors such as NotFound.
+	sentinel bool
+}
+
+// newSentinel returns a new package-level error.
+func newSentinel(code Code, msg string) *Error {
+	e := New(code, msg)
+	e.sentinel = true
+	return e
 }
 
 // Error returns the string representation of the error.
 func (e *Error) Error() string {
-	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	return fmt.Sprintf("[%s] %s", e.Code, e.message())
 }
 
-// HTTPStatusCode returns the HTTP status code for the error.
-func (e *Error) HTTPStatusCode() int {
-	switch e.Code {
-	case CodeBadRequest:
-		return http.StatusBadRequest
-	case CodeUnauthorized:
-		return http.StatusUnauthorized
-	case CodeForbidden:
-		return http.StatusForbidden
-	case CodeNotFound:
-		return http.StatusNotFound
-	case CodeGone:
-		return http.StatusGone
-	case CodeTooManyRequests:
-		return http.StatusTooManyRequests
-	case CodeInternalServerError:
-		return http.StatusInternalServerError
-	case CodeNotImplemented:
-		return http.StatusNotImplemented
-	case CodeServiceUnavailable:
-		return http.StatusServiceUnavailable
+// MarshalText implements encoding.TextMarshaler for log encoders such as
+// zap and zerolog. It returns the same "[CODE] message" form as Error.
+func (e *Error) MarshalText() ([]byte, error) {
+	return []byte(e.Error()), nil
+}
+
+// MarshalJSON implements json.Marshaler. The message is the public message
+// when set, and the values of info keys registered with RegisterRedactedKeys
+// are replaced with "[REDACTED]". Info values that cannot be marshaled are
+// replaced with their "%v" string, or their type for channels and functions,
+// and a warning is logged. The HTTP status code is included as "status" when
+// enabled with SetStatusInBody. The output is deterministic: map keys are
+// sorted at every level, as encoding/json and fmt do, and slices such as
+// Fields keep their order.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type plain Error
+	p := plain(*e)
+	p.Message = e.publicMessage()
+	p.DocsURL = e.docsURL()
+	p.Info = e.redactedInfo()
+
+	var v interface{} = &p
+	if currentConfig().statusInBody {
+		v = struct {
+			*plain
+			Status int `json:"status"`
+		}{&p, e.HTTPStatusCode()}
+	}
+
+	b, err := json.Marshal(v)
+	if err == nil || p.Info == nil {
+		return b, err
+	}
+
+	p.Info = sanitizeInfo(e.Code, p.Info)
+	return json.Marshal(v)
+}
+
+// sanitizeInfo returns a copy of info with the values that cannot be
+// marshaled to JSON replaced with a string, see unserializable.
+func sanitizeInfo(code Code, info map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(info))
+	for k, v := range info {
+		if _, err := json.Marshal(v); err != nil {
+			log(context.Background(), SeverityWarn, "errs: info value is not serializable", map[string]interface{}{
+				"code":  code,
+				"key":   k,
+				"error": err,
+			})
+			v = unserializable(v)
+		}
+
+		result[k] = v
+	}
+
+	return result
+}
+
+// unserializable returns the string written for a value that cannot be
+// marshaled: its type for channels and functions, whose "%v" string is an
+// address that changes across runs, and its "%v" string otherwise.
+func unserializable(v interface{}) string {
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return reflect.TypeOf(v).String()
 	default:
-		return http.StatusInternalServerError
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// publicMessage returns the message for clients.
+func (e *Error) publicMessage() string {
+	if e.PublicMessage != "" {
+		return e.interpolated(e.PublicMessage)
+	}
+
+	return e.message()
+}
+
+// publicError returns the string representation of the error for clients.
+func (e *Error) publicError() string {
+	return fmt.Sprintf("[%s] %s", e.Code, e.publicMessage())
+}
+
+// Unwrap returns the underlying error set with WithCause, if any.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// AsError returns the first *Error in the chain of err, as found by
+// errors.As, and whether there is one.
+//
+//	if e, ok := errs.AsError(err); ok && e.Code == errs.CodeNotFound {
+//		// ...
+//	}
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// StatusCode returns the HTTP status code of the first *Error in the chain of
+// err, see HTTPStatusCode, and 500 if there is none. Unlike ResponseError, it
+// does not apply the mappings, so sql.ErrNoRows is a 500. It is meant for
+// middleware that needs the status without writing a response, such as
+// access logs or metrics labels.
+func StatusCode(err error) int {
+	if e, ok := AsError(err); ok {
+		return e.HTTPStatusCode()
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Clone returns a deep copy of the error. The info is copied recursively so
+// the clone can be modified without affecting the original.
+func (e *Error) Clone() *Error {
+	c := *e
+	c.Info = cloneInfo(e.Info)
+	c.sentinel = false
+	if e.Fields != nil {
+		c.Fields = append([]FieldError(nil), e.Fields...)
+	}
+
+	return &c
+}
+
+// cloneInfo returns a deep copy of the info.
+func cloneInfo(info map[string]interface{}) map[string]interface{} {
+	if info == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(info))
+	for k, v := range info {
+		result[k] = cloneValue(v)
+	}
+
+	return result
+}
+
+// cloneValue returns a deep copy of maps and slices, and v otherwise.
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return cloneInfo(v)
+	case map[string]string:
+		result := make(map[string]string, len(v))
+		for k, s := range v {
+			result[k] = s
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = cloneValue(item)
+		}
+
+		return result
+	case []string:
+		return append([]string(nil), v...)
+	}
+
+	return v
+}
+
+// mergeInfo returns the info of outer deep merged with the info of inner.
+// Outer values win on conflicting keys, except that nested maps on both sides
+// are merged recursively. It returns outer itself if inner is empty.
+func mergeInfo(outer, inner map[string]interface{}) map[string]interface{} {
+	if len(inner) == 0 {
+		return outer
+	}
+
+	result := cloneInfo(inner)
+	for k, v := range outer {
+		innerMap, ok1 := result[k].(map[string]interface{})
+		outerMap, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			v = mergeInfo(outerMap, innerMap)
+		}
+
+		result[k] = v
+	}
+
+	return result
+}
+
+// WithInfoMap merges info into the info of the error, overwriting existing
+// keys, and returns the error. It mutates the error in place, except for the
+// package-level errors such as NotFound, which are cloned first.
+func (e *Error) WithInfoMap(info map[string]interface{}) *Error {
+	if e.sentinel {
+		e = e.Clone()
+	}
+
+	if e.Info == nil {
+		e.Info = make(map[string]interface{}, len(info))
+	}
+
+	for k, v := range info {
+		e.Info[k] = v
+	}
+
+	return e
+}
+
+// AppendInfo sets the info key to value, overwriting an existing value, and
+// returns the error. It mutates the error in place, except for the
+// package-level errors such as NotFound, which are cloned first.
+func (e *Error) AppendInfo(key string, value interface{}) *Error {
+	if e.sentinel {
+		e = e.Clone()
+	}
+
+	if e.Info == nil {
+		e.Info = make(map[string]interface{})
+	}
+
+	e.Info[key] = value
+	return e
+}
+
+// HTTPStatusCode returns the HTTP status code for the error. An error
+// returned by CheckResponse with a code unknown to the client keeps the
+// status of the response. When the code is unknown, the first *Error wrapped
+// below e with a known code or status is used before the default status.
+func (e *Error) HTTPStatusCode() int {
+	if status, ok := e.knownStatus(); ok {
+		return status
+	}
+
+	status, found := 0, false
+	walkChain(e, func(err error) bool {
+		if inner, ok := err.(*Error); ok {
+			status, found = inner.knownStatus()
+		}
+
+		return !found
+	})
+
+	if found {
+		return status
+	}
+
+	return currentConfig().defaultStatus
+}
+
+// IsRetryable reports whether the error represents a transient condition
+// worth retrying: CodeTooManyRequests, CodeServiceUnavailable, CodeTimeout,
+// or a code registered with CodeSpec.Retryable.
+func (e *Error) IsRetryable() bool {
+	spec, _ := codeSpec(e.Code)
+	return spec.Retryable
+}
+
+// knownStatus returns the HTTP status code of the error and whether it is
+// known from the decoded response or the code.
+func (e *Error) knownStatus() (int, bool) {
+	if e.status != 0 {
+		return e.status, true
+	}
+
+	return codeStatus(e.Code)
+}
+
+// now returns the current time, in UTC when enabled with SetUTCTimestamps.
+func now() time.Time {
+	if currentConfig().utcTimestamps {
+		return time.Now().UTC()
+	}
+
+	return time.Now()
+}
+
+// defaultMessage returns the message used when an error is created without
+// one: the registered default message, the HTTP status text for known codes,
+// or the code title otherwise.
+func defaultMessage(code Code) string {
+	spec, ok := codeSpec(code)
+	if ok && spec.DefaultMessage != "" {
+		return spec.DefaultMessage
+	}
+
+	if ok && http.StatusText(spec.HTTPStatus) != "" {
+		return http.StatusText(spec.HTTPStatus)
 	}
+
+	return code.Title()
 }
 
 // Option represents an option for an error.
@@ -99,8 +457,46 @@ type Option func(*option)
 
 // option represents an option.
 type option struct {
-	info   map[string]interface{}
-	logErr error
+	ctx     context.Context
+	info    map[string]interface{}
+	fields  []FieldError
+	data    interface{}
+	docsURL string
+	logErr  error
+	cause   error
+	headers map[string]string
+
+	publicMessage  string
+	severity       Severity
+	timestamp      time.Time
+	rejectedValues bool
+	validationCode Code
+	interpolate    bool
+	nest           bool
+	validation     bool
+	status         int
+	validationMsgs map[string]interface{}
+	lazyMessage    func() string
+	infoFunc       func() map[string]interface{}
+}
+
+// setDefaultInfo sets the info key to value unless the key is already set.
+func (o *option) setDefaultInfo(key string, value interface{}) {
+	if _, ok := o.info[key]; !ok {
+		o.setInfo(key, value)
+	}
+}
+
+// setInfo sets the info key to value. The info passed with WithInfo is
+// copied rather than modified.
+func (o *option) setInfo(key string, value interface{}) {
+	info := make(map[string]interface{}, len(o.info)+1)
+	for k, v := range o.info {
+		info[k] = v
+	}
+
+	info[key] = value
+	o.info = info
 }
 
 // WithInfo sets the info option.
@@ -110,6 +506,38 @@ func WithInfo(info map[string]interface{}) Option {
 	}
 }
 
+// WithData sets the typed payload of the error, returned to clients as
+// "data".
+//
+//	errs.New(errs.CodeTooManyRequests, "", errs.WithData(Quota{Remaining: 0}))
+func WithData(v interface{}) Option {
+	return func(o *option) {
+		o.data = v
+	}
+}
+
+// WithDocsURL sets the link to the documentation of the error, returned to
+// clients as "docs". It takes precedence over SetDocsURLTemplate.
+func WithDocsURL(url string) Option {
+	return func(o *option) {
+		o.docsURL = url
+	}
+}
+
+// docsURL returns the documentation link of the error: DocsURL, or the
+// docs URL template with the code when set.
+func (e *Error) docsURL() string {
+	if e.DocsURL != "" {
+		return e.DocsURL
+	}
+
+	if tmpl := currentConfig().docsURLTemplate; tmpl != "" {
+		return strings.ReplaceAll(tmpl, "{code}", e.Code.String())
+	}
+
+	return ""
+}
+
 // WithLogErr sets the log error option.
 func WithLogErr(err error) Option {
 	return func(o *option) {
@@ -117,74 +545,219 @@ func WithLogErr(err error) Option {
 	}
 }
 
-// New returns a new error.
-func New(code Code, msg string, opts ...Option) *Error {
-	o := new(option)
-	for _, opt := range opts {
-		opt(o)
+// WithPublicMessage sets the message returned to clients, keeping the
+// message passed to New for logs.
+func WithPublicMessage(msg string) Option {
+	return func(o *option) {
+		o.publicMessage = msg
 	}
+}
 
-	if o.logErr != nil {
-		logrus.WithError(o.logErr).Error(msg)
+// WithTimestamp sets the time the error occurred, e.g. when reconstructing
+// an error from an event log. A zero time is ignored.
+func WithTimestamp(t time.Time) Option {
+	return func(o *option) {
+		o.timestamp = t
 	}
+}
 
-	e := &Error{
-		Code:      code,
-		Message:   msg,
-		Timestamp: time.Now(),
-		Info:      o.info,
+// WithSeverity sets the severity the error is logged at.
+func WithSeverity(severity Severity) Option {
+	return func(o *option) {
+		o.severity = severity
 	}
-
-	return e
 }
 
-// InvalidStructError returns a new error for an invalid struct.
-func InvalidStructError(err error) *Error {
-	return New(CodeBadRequest, http.StatusText(http.StatusBadRequest), WithInfo(validationInfo(err)))
+// WithCause sets the underlying error. The cause is returned by Unwrap and
+// logged as the "cause" field, but never serialized into the response. If
+// the cause wraps an *Error, its info is merged into the new error, see Wrap.
+func WithCause(err error) Option {
+	return func(o *option) {
+		o.cause = err
+	}
 }
 
-// validationInfo returns the validation info for the error.
-func validationInfo(err error) map[string]interface{} {
-	result := make(map[string]interface{})
-	if errCast, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range errCast {
-			result[strcase.ToLowerCamel(e.Field())] = toMessage(e)
+// WithHeaders sets response headers, such as X-RateLimit-Remaining or
+// Location, written by ResponseError and WriteError. Headers of several
+// WithHeaders options are merged.
+func WithHeaders(headers map[string]string) Option {
+	return func(o *option) {
+		merged := make(map[string]string, len(o.headers)+len(headers))
+		for k, v := range o.headers {
+			merged[k] = v
+		}
+		for k, v := range headers {
+			merged[k] = v
 		}
 
-		return result
+		o.headers = merged
+	}
+}
+
+// WithContext sets the context option. The context is passed to the logger
+// and to the extractor set by SetContextInfoExtractor.
+func WithContext(ctx context.Context) Option {
+	return func(o *option) {
+		o.ctx = ctx
+	}
+}
+
+// optionPool holds the options applied by NewCtx, which would otherwise be
+// allocated for every error created with options.
+var optionPool = sync.Pool{
+	New: func() interface{} { return new(option) },
+}
+
+// applyOptions returns the options applied to the defaults. The option is
+// returned by value so that it stays on the stack of the caller.
+func applyOptions(ctx context.Context, opts []Option) option {
+	if len(opts) == 0 {
+		return option{ctx: ctx}
+	}
+
+	o := optionPool.Get().(*option)
+	*o = option{ctx: ctx}
+	for _, opt := range opts {
+		opt(o)
 	}
 
-	result["error"] = err.Error()
+	result := *o
+	*o = option{}
+	optionPool.Put(o)
 	return result
 }
 
-// toMessage returns the message for the validation error.
-func toMessage(e validator.FieldError) string {
-	switch e.Tag() {
-	case "required":
-		return fmt.Sprintf("%s is required", strcase.ToLowerCamel(e.Field()))
-	case "max":
-		return fmt.Sprintf("%s cannot be longer than %s", strcase.ToLowerCamel(e.Field()), e.Param())
-	case "min":
-		return fmt.Sprintf("%s must be longer than %s", strcase.ToLowerCamel(e.Field()), e.Param())
-	case "email":
-		return "invalid email format"
-	case "len":
-		return fmt.Sprintf("%s must be %s characters long", strcase.ToLowerCamel(e.Field()), e.Param())
-	case "oneof":
-		return fmt.Sprintf("%s must be %s", strcase.ToLowerCamel(e.Field()), e.Param())
+// causeInfo returns the info of the first *Error in the chain of cause.
+func causeInfo(cause error) map[string]interface{} {
+	if inner, ok := AsError(cause); ok {
+		return inner.Info
 	}
 
-	return fmt.Sprintf("%s is not valid", strcase.ToLowerCamel(e.Field()))
+	return nil
 }
 
-// ResponseError returns an error response.
-func ResponseError(c *gin.Context, err error) {
-	var e *Error
-	if ok := errors.As(err, &e); ok {
-		c.JSON(e.HTTPStatusCode(), e)
-		return
+// autoLog reports whether the error is logged by the auto log policy.
+func autoLog(e *Error) bool {
+	c := currentConfig()
+	return c.autoLog && e.HTTPStatusCode() >= c.autoLogThreshold
+}
+
+// New returns a new error. If msg is empty, a default message derived from
+// the code is used.
+func New(code Code, msg string, opts ...Option) *Error {
+	return NewCtx(context.Background(), code, msg, opts...)
+}
+
+// FromStatus returns a new error for the HTTP status code, with the code
+// returned by CodeFromStatus. The message is msg when given, and the HTTP
+// status text otherwise. Unmapped statuses become a CodeInternalServerError
+// with its default message.
+//
+//	if resp.StatusCode >= 400 {
+//		return errs.FromStatus(resp.StatusCode)
+//	}
+func FromStatus(status int, msg ...string) *Error {
+	code := CodeFromStatus(status)
+
+	var m string
+	if len(msg) > 0 {
+		m = msg[0]
+	}
+
+	if m == "" {
+		if s, _ := codeStatus(code); s == status {
+			m = http.StatusText(status)
+		}
+	}
+
+	return New(code, m)
+}
+
+// NewCtx returns a new error like New, passing ctx to the logger. The request
+// ID and trace ID stored in ctx are added to the info as "requestID" and
+// "traceId", unless the info already has these keys.
+func NewCtx(ctx context.Context, code Code, msg string, opts ...Option) *Error {
+	o := applyOptions(ctx, opts)
+	if o.cause != nil {
+		o.info = mergeInfo(o.info, causeInfo(o.cause))
+		if inner, ok := AsError(o.cause); ok {
+			o.validationMsgs = mergeInfo(o.validationMsgs, inner.validationMessages)
+		}
+	}
+
+	if id := RequestIDFromContext(o.ctx); id != "" {
+		o.setDefaultInfo("requestID", id)
+	}
+
+	if id := TraceIDFromContext(o.ctx); id != "" {
+		o.setDefaultInfo("traceId", id)
+	}
+
+	if o.validation && o.validationCode != "" {
+		code = o.validationCode
+	}
+
+	if msg == "" && o.lazyMessage == nil {
+		msg = defaultMessage(code)
+	}
+
+	e := &Error{
+		Code:          code,
+		Message:       msg,
+		PublicMessage: o.publicMessage,
+		Timestamp:     o.timestamp,
+		Info:          o.info,
+		Fields:        o.fields,
+		Data:          o.data,
+		DocsURL:       o.docsURL,
+		Severity:      o.severity,
+		cause:         o.cause,
+		headers:       o.headers,
+		interpolate:   o.interpolate,
+		status:        o.status,
+
+		validationMessages: o.validationMsgs,
+	}
+
+	if o.lazyMessage != nil {
+		e.lazy = &lazyMessage{fn: o.lazyMessage}
+	}
+
+	if o.infoFunc != nil {
+		e.lazyInfo = &lazyInfo{fn: o.infoFunc}
+	}
+
+	if e.Timestamp.IsZero() {
+		e.Timestamp = now()
+	}
+
+	if e.Severity == 0 {
+		e.Severity = defaultSeverity(e.HTTPStatusCode())
+	}
+
+	if o.logErr != nil || autoLog(e) {
+		fields := map[string]interface{}{"code": e.Code}
+		if info := e.redactedInfo(); len(info) > 0 {
+			fields["info"] = info
+		}
+
+		if o.logErr != nil {
+			fields["error"] = o.logErr
+		}
+
+		if o.cause != nil {
+			fields["cause"] = o.cause
+		}
+
+		if extract := currentConfig().contextInfo; extract != nil && o.ctx != nil {
+			for k, v := range extract(o.ctx) {
+				fields[k] = v
+			}
+		}
+
+		log(o.ctx, e.Severity, e.message(), fields)
 	}
 
-	c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	runErrorHooks(e)
+	return e
 }
@@ -2,17 +2,45 @@ package errs_test
 
 import (
 	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/thirathawat/errs"
 )
 
+func TestCodeTitle(t *testing.T) {
+	tests := []struct {
+		code errs.Code
+		want string
+	}{
+		{errs.CodeTooManyRequests, "Too Many Requests"},
+		{errs.CodeNotFound, "Not Found"},
+		{errs.CodeGone, "Gone"},
+		{errs.Code("PAYMENT_DECLINED"), "Payment Declined"},
+		{errs.Code("quota_exceeded"), "Quota Exceeded"},
+		{errs.Code("NOT_FOUND_V2"), "Not Found V2"},
+		{errs.Code("HTTP2_ERROR"), "Http2 Error"},
+		{errs.Code("rate-limited"), "Rate Limited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.code.Title())
+		})
+	}
+}
+
 func TestNewError(t *testing.T) {
 	err := errs.New(errs.CodeBadRequest, "Bad request")
 	assert.NotNil(t, err)
@@ -22,6 +50,99 @@ func TestNewError(t *testing.T) {
 	assert.False(t, err.Timestamp.IsZero())
 }
 
+func TestNewErrorWithEmptyMessage(t *testing.T) {
+	tests := []struct {
+		code errs.Code
+		want string
+	}{
+		{errs.CodeBadRequest, "Bad Request"},
+		{errs.CodeUnauthorized, "Unauthorized"},
+		{errs.CodePaymentRequired, "Payment Required"},
+		{errs.CodeForbidden, "Forbidden"},
+		{errs.CodeNotFound, "Not Found"},
+		{errs.CodeConflict, "Conflict"},
+		{errs.CodeGone, "Gone"},
+		{errs.CodeUnprocessableEntity, "Unprocessable Entity"},
+		{errs.CodeTooManyRequests, "Too Many Requests"},
+		{errs.CodeUnavailableForLegalReasons, "Unavailable For Legal Reasons"},
+		{errs.CodeInternalServerError, "Internal Server Error"},
+		{errs.CodeNotImplemented, "Not Implemented"},
+		{errs.CodeServiceUnavailable, "Service Unavailable"},
+		{errs.CodeTimeout, "Gateway Timeout"},
+		{errs.CodeClientClosedRequest, "Client Closed Request"},
+		{errs.Code("PAYMENT_DECLINED"), "Payment Declined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := errs.New(tt.code, "")
+			assert.Equal(t, tt.want, err.Message)
+			assert.Equal(t, "["+tt.code.String()+"] "+tt.want, err.Error())
+		})
+	}
+}
+
+func TestNewErrorWithTimestamp(t *testing.T) {
+	ts := time.Date(2023, time.June, 1, 12, 30, 0, 0, time.UTC)
+	err := errs.New(errs.CodeNotFound, "Not found", errs.WithTimestamp(ts))
+	assert.Equal(t, ts, err.Timestamp)
+
+	err = errs.New(errs.CodeNotFound, "Not found", errs.WithTimestamp(time.Time{}))
+	assert.False(t, err.Timestamp.IsZero())
+}
+
+func TestHTTPStatusCode(t *testing.T) {
+	tests := []struct {
+		code errs.Code
+		want int
+	}{
+		{errs.CodeBadRequest, http.StatusBadRequest},
+		{errs.CodeUnauthorized, http.StatusUnauthorized},
+		{errs.CodePaymentRequired, http.StatusPaymentRequired},
+		{errs.CodeForbidden, http.StatusForbidden},
+		{errs.CodeNotFound, http.StatusNotFound},
+		{errs.CodeConflict, http.StatusConflict},
+		{errs.CodeGone, http.StatusGone},
+		{errs.CodeUnprocessableEntity, http.StatusUnprocessableEntity},
+		{errs.CodeTooManyRequests, http.StatusTooManyRequests},
+		{errs.CodeUnavailableForLegalReasons, http.StatusUnavailableForLegalReasons},
+		{errs.CodeInternalServerError, http.StatusInternalServerError},
+		{errs.CodeNotImplemented, http.StatusNotImplemented},
+		{errs.CodeServiceUnavailable, http.StatusServiceUnavailable},
+		{errs.CodeTimeout, http.StatusGatewayTimeout},
+		{errs.CodeClientClosedRequest, errs.StatusClientClosedRequest},
+		{errs.Code("UNKNOWN"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, errs.New(tt.code, "").HTTPStatusCode())
+		})
+	}
+}
+
+func TestSetDefaultStatus(t *testing.T) {
+	assert.NoError(t, errs.SetDefaultStatus(http.StatusBadGateway))
+	t.Cleanup(func() { _ = errs.SetDefaultStatus(http.StatusInternalServerError) })
+
+	assert.Error(t, errs.SetDefaultStatus(0))
+	assert.Error(t, errs.SetDefaultStatus(1000))
+
+	assert.Equal(t, http.StatusBadGateway, errs.New(errs.Code("UNMAPPED"), "").HTTPStatusCode())
+	assert.Equal(t, http.StatusNotFound, errs.NotFound.HTTPStatusCode())
+	assert.Equal(t, http.StatusInternalServerError, errs.InternalServerError.HTTPStatusCode())
+}
+
+func TestConflictAndUnprocessableEntity(t *testing.T) {
+	assert.Equal(t, errs.CodeConflict, errs.Conflict.Code)
+	assert.Equal(t, "Conflict", errs.Conflict.Message)
+	assert.Equal(t, http.StatusConflict, errs.Conflict.HTTPStatusCode())
+
+	assert.Equal(t, errs.CodeUnprocessableEntity, errs.UnprocessableEntity.Code)
+	assert.Equal(t, "Unprocessable Entity", errs.UnprocessableEntity.Message)
+	assert.Equal(t, http.StatusUnprocessableEntity, errs.UnprocessableEntity.HTTPStatusCode())
+}
+
 func TestNewErrorWithInfo(t *testing.T) {
 	info := map[string]interface{}{
 		"field": "value",
@@ -36,6 +157,158 @@ func TestNewErrorWithInfo(t *testing.T) {
 	assert.False(t, err.Timestamp.IsZero())
 }
 
+func TestNewErrorWithContextInfoExtractor(t *testing.T) {
+	type tenantKey struct{}
+
+	errs.SetContextInfoExtractor(func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{"tenant": ctx.Value(tenantKey{})}
+	})
+	t.Cleanup(func() { errs.SetContextInfoExtractor(nil) })
+
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	err := errs.New(errs.CodeInternalServerError, "Internal server error",
+		errs.WithLogErr(errors.New("db is down")),
+		errs.WithContext(ctx),
+	)
+
+	entry := lastEntry(l)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "acme", entry.Fields["tenant"])
+		assert.Equal(t, "Internal server error", entry.Message)
+	}
+	assert.Empty(t, err.Info)
+}
+
+func TestNewErrorWithCause(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	cause := errors.New("connection refused")
+	err := errs.New(errs.CodeServiceUnavailable, "Service unavailable",
+		errs.WithCause(cause),
+		errs.WithLogErr(errors.New("payment gateway failed")),
+	)
+	assert.Equal(t, cause, errors.Unwrap(err))
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "[SERVICE_UNAVAILABLE] Service unavailable", err.Error())
+
+	entry := lastEntry(l)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, cause, entry.Fields["cause"])
+	}
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.NotContains(t, string(body), "connection refused")
+}
+
+func TestMarshalJSONWithUnserializableInfo(t *testing.T) {
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	err := errs.New(errs.CodeInternalServerError, "Internal server error", errs.WithInfo(map[string]interface{}{
+		"userId":   42,
+		"channel":  make(chan int),
+		"callback": func() {},
+	}))
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+
+	var got struct {
+		Info map[string]interface{} `json:"info"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, float64(42), got.Info["userId"])
+	assert.Equal(t, "chan int", got.Info["channel"])
+	assert.Equal(t, "func()", got.Info["callback"])
+	assert.Len(t, l.entries, 2)
+	assert.Equal(t, errs.SeverityWarn, l.entries[0].severity)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/unserializable", func(c *gin.Context) {
+		errs.ResponseError(c, err)
+	})
+
+	w := performRequest(router, http.MethodGet, "/unserializable", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.True(t, json.Valid(w.Body.Bytes()))
+}
+
+func TestAppendInfo(t *testing.T) {
+	err := errs.New(errs.CodeNotFound, "User not found")
+	assert.Nil(t, err.Info)
+
+	got := err.AppendInfo("userId", 42).AppendInfo("tenant", "acme")
+	assert.Same(t, err, got)
+	assert.Equal(t, map[string]interface{}{"userId": 42, "tenant": "acme"}, err.Info)
+
+	err.AppendInfo("userId", 43)
+	assert.Equal(t, 43, err.Info["userId"])
+}
+
+func TestWithInfoMap(t *testing.T) {
+	err := errs.New(errs.CodeNotFound, "User not found")
+	got := err.WithInfoMap(map[string]interface{}{"userId": 42})
+	assert.Same(t, err, got)
+	assert.Equal(t, map[string]interface{}{"userId": 42}, err.Info)
+
+	err.WithInfoMap(map[string]interface{}{"userId": 43, "tenant": "acme"})
+	assert.Equal(t, map[string]interface{}{"userId": 43, "tenant": "acme"}, err.Info)
+}
+
+func TestClone(t *testing.T) {
+	err := errs.New(errs.CodeNotFound, "User not found", errs.WithInfo(map[string]interface{}{
+		"user": map[string]interface{}{"id": 42},
+		"tags": []interface{}{"a"},
+	}))
+
+	clone := err.Clone()
+	assert.Equal(t, err, clone)
+	assert.NotSame(t, err, clone)
+
+	clone.Message = "Account not found"
+	clone.Info["user"].(map[string]interface{})["id"] = 43
+	clone.Info["tags"].([]interface{})[0] = "b"
+	clone.AppendInfo("tenant", "acme")
+
+	assert.Equal(t, "User not found", err.Message)
+	assert.Equal(t, 42, err.Info["user"].(map[string]interface{})["id"])
+	assert.Equal(t, "a", err.Info["tags"].([]interface{})[0])
+	assert.NotContains(t, err.Info, "tenant")
+	assert.Equal(t, err.Timestamp, clone.Timestamp)
+}
+
+func TestCloneDoesNotAffectSentinel(t *testing.T) {
+	clone := errs.NotFound.Clone()
+	clone.Message = "User not found"
+	clone.AppendInfo("userId", 42)
+
+	assert.Equal(t, "Not Found", errs.NotFound.Message)
+	assert.Nil(t, errs.NotFound.Info)
+}
+
+func TestAppendInfoClonesSentinel(t *testing.T) {
+	err := errs.NotFound.AppendInfo("userId", 42)
+	assert.NotSame(t, errs.NotFound, err)
+	assert.Equal(t, 42, err.Info["userId"])
+	assert.Nil(t, errs.NotFound.Info)
+
+	err = errs.Forbidden.WithInfoMap(map[string]interface{}{"role": "guest"})
+	assert.NotSame(t, errs.Forbidden, err)
+	assert.Equal(t, "guest", err.Info["role"])
+	assert.Nil(t, errs.Forbidden.Info)
+
+	assert.Same(t, err, err.AppendInfo("userId", 42), "clones are mutated in place")
+}
+
 func TestInvalidStructError(t *testing.T) {
 	type test struct {
 		Field string `json:"field" binding:"required"`
@@ -58,6 +331,18 @@ func TestInvalidStructError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestInvalidStructErrorWithMap(t *testing.T) {
+	type test struct {
+		Prices map[string]int `validate:"dive,keys,min=1,endkeys,gt=0"`
+	}
+
+	err := validator.New().Struct(test{Prices: map[string]int{"sku1": 0, "sku2": 10}})
+	e := errs.InvalidStructError(err)
+	assert.Equal(t, errs.CodeBadRequest, e.Code)
+	assert.Len(t, e.Info, 1)
+	assert.Equal(t, "prices[sku1] is not valid", e.Info["prices[sku1]"])
+}
+
 func TestResponseErrorWithErrsError(t *testing.T) {
 	err := errs.New(errs.CodeNotFound, "Not found")
 	gin.SetMode(gin.TestMode)
@@ -71,6 +356,34 @@ func TestResponseErrorWithErrsError(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestResponseErrorWithCodeHeader(t *testing.T) {
+	errs.SetEmitCodeHeader("X-Error-Code")
+	t.Cleanup(func() { errs.SetEmitCodeHeader("") })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/header", func(c *gin.Context) {
+		errs.ResponseError(c, errs.Forbidden)
+	})
+
+	w := performRequest(router, http.MethodGet, "/header", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, errs.CodeForbidden.String(), w.Header().Get("X-Error-Code"))
+}
+
+func TestResponseErrorWithoutCodeHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/header", func(c *gin.Context) {
+		errs.ResponseError(c, errs.Forbidden)
+	})
+
+	w := performRequest(router, http.MethodGet, "/header", nil)
+	assert.Empty(t, w.Header().Get("X-Error-Code"))
+}
+
 func TestResponseErrorWithNonErrsError(t *testing.T) {
 	err := errors.New("Some error")
 	gin.SetMode(gin.TestMode)
@@ -84,9 +397,176 @@ func TestResponseErrorWithNonErrsError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestResponseErrorAborts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var called bool
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeUnauthorized, "Missing token"))
+	}, func(c *gin.Context) {
+		called = true
+		c.String(http.StatusOK, "user")
+	})
+
+	w := performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.False(t, called)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Missing token", body["message"])
+}
+
 func performRequest(router *gin.Engine, method, path string, body io.Reader) *httptest.ResponseRecorder {
 	req, _ := http.NewRequest(method, path, body)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	return w
 }
+
+func TestSetStatusInBody(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := errs.New(errs.CodeTooManyRequests, "Slow down", errs.WithTimestamp(ts))
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.NotContains(t, string(body), `"status"`)
+
+	errs.SetStatusInBody(true)
+	t.Cleanup(func() { errs.SetStatusInBody(false) })
+
+	body, jsonErr = json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.JSONEq(t, `{"code":"TOO_MANY_REQUESTS","message":"Slow down","timestamp":"2023-06-01T12:00:00Z","status":429}`, string(body))
+
+	body, jsonErr = json.Marshal(errs.New(errs.CodeNotFound, "", errs.WithInfo(map[string]interface{}{"ch": make(chan int)})))
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"status":404`)
+}
+
+func TestWithData(t *testing.T) {
+	type quota struct {
+		Limit     int       `json:"limit"`
+		Remaining int       `json:"remaining"`
+		ResetAt   time.Time `json:"resetAt"`
+	}
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	want := quota{Limit: 100, Remaining: 0, ResetAt: ts.Add(time.Hour)}
+	err := errs.New(errs.CodeTooManyRequests, "Quota exceeded", errs.WithData(want), errs.WithTimestamp(ts))
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.JSONEq(t, `{
+		"code": "TOO_MANY_REQUESTS",
+		"message": "Quota exceeded",
+		"data": {"limit": 100, "remaining": 0, "resetAt": "2023-06-01T13:00:00Z"},
+		"timestamp": "2023-06-01T12:00:00Z"
+	}`, string(body))
+
+	var got quota
+	decoded := errs.Error{Data: &got}
+	assert.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, want, got)
+
+	body, jsonErr = json.Marshal(errs.New(errs.CodeNotFound, ""))
+	assert.NoError(t, jsonErr)
+	assert.NotContains(t, string(body), `"data"`)
+}
+
+func TestMarshalJSONDeterministic(t *testing.T) {
+	errs.RegisterRedactedKeys("token")
+	t.Cleanup(errs.ResetRedactedKeys)
+
+	type order struct {
+		Sku string `validate:"required"`
+		Qty int    `validate:"min=1"`
+	}
+
+	newErr := func() *errs.Error {
+		return errs.InvalidStructError(validator.New().Struct(order{}),
+			errs.WithTimestamp(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)),
+			errs.WithInfo(map[string]interface{}{
+				"zone":   "eu",
+				"token":  "secret",
+				"items":  []interface{}{map[string]interface{}{"sku": "b2", "qty": 1}, map[string]interface{}{"sku": "a1", "qty": 2}},
+				"counts": map[int]int{3: 1, 1: 2, 2: 3},
+				"nested": map[string]interface{}{"y": map[string]string{"d": "4", "c": "3"}, "x": true},
+				"ch":     make(chan int),
+				"byCode": map[interface{}]string{"b": "2", "a": "1"},
+			}),
+		)
+	}
+
+	want, jsonErr := json.Marshal(newErr())
+	assert.NoError(t, jsonErr)
+	for i := 0; i < 20; i++ {
+		got, jsonErr := json.Marshal(newErr())
+		assert.NoError(t, jsonErr)
+		assert.Equal(t, string(want), string(got))
+	}
+
+	assert.Contains(t, string(want), `"counts":{"1":2,"2":3,"3":1}`)
+	assert.Contains(t, string(want), `"nested":{"x":true,"y":{"c":"3","d":"4"}}`)
+	assert.Contains(t, string(want), `"byCode":{"a":"1","b":"2"}`)
+	assert.Contains(t, string(want), `"ch":"chan int"`)
+	assert.Contains(t, string(want), `"fields":[{"field":"sku"`)
+}
+
+func TestSetUTCTimestamps(t *testing.T) {
+	errs.SetUTCTimestamps(true)
+	t.Cleanup(func() { errs.SetUTCTimestamps(false) })
+
+	err := errs.New(errs.CodeNotFound, "User not found")
+	assert.Equal(t, time.UTC, err.Timestamp.Location())
+	assert.WithinDuration(t, time.Now(), err.Timestamp, time.Minute)
+
+	local := time.Date(2023, 6, 1, 12, 0, 0, 0, time.FixedZone("ICT", 7*60*60))
+	err = errs.New(errs.CodeNotFound, "User not found", errs.WithTimestamp(local))
+	assert.Equal(t, local, err.Timestamp)
+
+	errs.SetUTCTimestamps(false)
+	err = errs.New(errs.CodeNotFound, "User not found")
+	assert.Equal(t, time.Local, err.Timestamp.Location())
+}
+
+func TestMarshalText(t *testing.T) {
+	err := errs.New(errs.CodeNotFound, "User not found", errs.WithPublicMessage("Not here"))
+
+	var m encoding.TextMarshaler = err
+	text, textErr := m.MarshalText()
+	assert.NoError(t, textErr)
+	assert.Equal(t, err.Error(), string(text))
+	assert.Equal(t, "[NOT_FOUND] User not found", string(text))
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"code":"NOT_FOUND"`)
+}
+
+func TestDocsURL(t *testing.T) {
+	err := errs.New(errs.CodeTooManyRequests, "Slow down", errs.WithDocsURL("https://docs.example.com/rate-limits"))
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"docs":"https://docs.example.com/rate-limits"`)
+
+	body, jsonErr = json.Marshal(errs.New(errs.CodeNotFound, ""))
+	assert.NoError(t, jsonErr)
+	assert.NotContains(t, string(body), `"docs"`)
+
+	errs.SetDocsURLTemplate("https://docs.example.com/errors/{code}")
+	t.Cleanup(func() { errs.SetDocsURLTemplate("") })
+
+	body, jsonErr = json.Marshal(errs.NotFound)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"docs":"https://docs.example.com/errors/NOT_FOUND"`)
+
+	body, jsonErr = json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"docs":"https://docs.example.com/rate-limits"`)
+
+	body, xmlErr := xml.Marshal(errs.NotFound)
+	assert.NoError(t, xmlErr)
+	assert.Contains(t, string(body), `<docs>https://docs.example.com/errors/NOT_FOUND</docs>`)
+}
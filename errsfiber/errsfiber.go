@@ -0,0 +1,34 @@
+// Package errsfiber writes errs errors from Fiber applications.
+package errsfiber
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thirathawat/errs"
+)
+
+// FiberErrorHandler is a fiber.ErrorHandler that writes the error like
+// errs.WriteError does, with the envelope settings, the headers set with
+// errs.WithHeaders and the code header. A *fiber.Error that does not wrap an
+// *errs.Error is converted with errs.CodeFromStatus. The request ID is taken
+// from the user context of the request, see errs.ContextWithRequestID.
+//
+//	app := fiber.New(fiber.Config{ErrorHandler: errsfiber.FiberErrorHandler})
+func FiberErrorHandler(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if e, ok := errs.Resolve(err); ok {
+		err = e
+	} else if errors.As(err, &fe) {
+		err = errs.New(errs.CodeFromStatus(fe.Code), fe.Message, errs.WithCause(err))
+	}
+
+	resp := errs.NewResponse(err, errs.RequestIDFromContext(c.UserContext()))
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			c.Set(k, v)
+		}
+	}
+
+	return c.Status(resp.Status).Send(resp.Body)
+}
@@ -0,0 +1,119 @@
+package errsfiber_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+	"github.com/thirathawat/errs/errsfiber"
+)
+
+type body struct {
+	Code    errs.Code `json:"code"`
+	Message string    `json:"message"`
+}
+
+func TestFiberErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: errsfiber.FiberErrorHandler})
+	app.Get("/errs", func(c *fiber.Ctx) error {
+		return errs.New(errs.CodeConflict, "Email already taken")
+	})
+	app.Get("/fiber", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
+	})
+	app.Get("/batch", func(c *fiber.Ctx) error {
+		return errs.Join(errs.NotFound, errs.New(errs.CodeServiceUnavailable, "Inventory unavailable"))
+	})
+
+	tests := []struct {
+		path    string
+		status  int
+		code    errs.Code
+		message string
+	}{
+		{"/errs", http.StatusConflict, errs.CodeConflict, "Email already taken"},
+		{"/fiber", http.StatusForbidden, errs.CodeForbidden, "Access denied"},
+		{"/missing", http.StatusNotFound, errs.CodeNotFound, "Cannot GET /missing"},
+		{"/batch", http.StatusServiceUnavailable, errs.CodeServiceUnavailable, "Inventory unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, fiber.MIMEApplicationJSONCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+
+			var got body
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+			assert.Equal(t, body{Code: tt.code, Message: tt.message}, got)
+		})
+	}
+}
+
+func TestFiberErrorHandlerWriteErrorParity(t *testing.T) {
+	errs.SetEmitCodeHeader("X-Error-Code")
+	errs.SetEnvelope(errs.EnvelopeNested)
+	t.Cleanup(func() {
+		errs.SetEmitCodeHeader("")
+		errs.SetEnvelope(errs.EnvelopeFlat)
+	})
+
+	app := fiber.New(fiber.Config{ErrorHandler: errsfiber.FiberErrorHandler})
+	app.Get("/limited", func(c *fiber.Ctx) error {
+		return errs.New(errs.CodeTooManyRequests, "", errs.WithHeaders(map[string]string{"Retry-After": "30"}))
+	})
+	app.Get("/plain", func(c *fiber.Ctx) error {
+		return errors.New("boom")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/limited", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+	assert.Equal(t, "TOO_MANY_REQUESTS", resp.Header.Get("X-Error-Code"))
+
+	var got struct {
+		Error body `json:"error"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, errs.CodeTooManyRequests, got.Error.Code)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/plain", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestFiberErrorHandlerResolvesOnce(t *testing.T) {
+	errOutOfStock := errors.New("out of stock")
+	errs.RegisterMapping(errOutOfStock, errs.CodeConflict, "Item is out of stock")
+
+	var calls int
+	errs.OnError(func(e *errs.Error) {
+		if errors.Is(e, errOutOfStock) {
+			calls++
+		}
+	})
+
+	app := fiber.New(fiber.Config{ErrorHandler: errsfiber.FiberErrorHandler})
+	app.Post("/cart", func(c *fiber.Ctx) error {
+		return errOutOfStock
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/cart", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,42 @@
+// Package errslogrus logs errs errors with logrus.
+package errslogrus
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/thirathawat/errs"
+)
+
+// Logger is an errs.Logger that logs with logrus. The zero value logs with
+// the logrus standard logger.
+//
+//	errs.SetLogger(errslogrus.Logger{})
+type Logger struct {
+	// Logger is the logrus logger, the standard logger when nil.
+	Logger *logrus.Logger
+}
+
+// Log implements errs.Logger.
+func (l Logger) Log(ctx context.Context, severity errs.Severity, msg string, fields map[string]interface{}) {
+	logger := l.Logger
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	logger.WithContext(ctx).WithFields(fields).Log(level(severity), msg)
+}
+
+// level returns the logrus level for the severity.
+func level(severity errs.Severity) logrus.Level {
+	switch severity {
+	case errs.SeverityDebug:
+		return logrus.DebugLevel
+	case errs.SeverityInfo:
+		return logrus.InfoLevel
+	case errs.SeverityWarn:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
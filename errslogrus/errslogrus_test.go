@@ -0,0 +1,58 @@
+package errslogrus_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+	"github.com/thirathawat/errs/errslogrus"
+)
+
+func TestNoLoggingByDefault(t *testing.T) {
+	hook := test.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	errs.New(errs.CodeInternalServerError, "Internal server error", errs.WithLogErr(errors.New("db is down")))
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestLogger(t *testing.T) {
+	hook := test.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	errs.SetLogger(errslogrus.Logger{})
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.New(errs.CodeBadRequest, "Bad request", errs.WithLogErr(errors.New("invalid body")))
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Equal(t, "Bad request", entry.Message)
+		assert.Equal(t, errs.CodeBadRequest, entry.Data["code"])
+	}
+
+	errs.New(errs.CodeInternalServerError, "Internal server error", errs.WithLogErr(errors.New("db is down")))
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.ErrorLevel, entry.Level)
+	}
+
+	errs.New(errs.CodeNotFound, "Not found", errs.WithLogErr(errors.New("cache miss")), errs.WithSeverity(errs.SeverityInfo))
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.InfoLevel, entry.Level)
+	}
+}
+
+func TestLoggerWithLogger(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	l.SetLevel(logrus.DebugLevel)
+
+	errs.SetLogger(errslogrus.Logger{Logger: l})
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.New(errs.CodeNotFound, "Not found", errs.WithLogErr(errors.New("cache miss")), errs.WithSeverity(errs.SeverityDebug))
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.DebugLevel, entry.Level)
+	}
+}
@@ -0,0 +1,42 @@
+// Package errsotel records errs errors on OpenTelemetry spans.
+package errsotel
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thirathawat/errs"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ResponseErrorCtx returns an error response like errs.ResponseError and
+// records the error on the span in the request context, if any. The trace ID
+// of the span is added as info.traceId to the error resolved with
+// errs.Resolve, so aggregated and mapped errors carry it too.
+func ResponseErrorCtx(c *gin.Context, err error) {
+	span := trace.SpanFromContext(c.Request.Context())
+	if span.IsRecording() {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, message(err))
+	}
+
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		if e, ok := errs.Resolve(err); ok {
+			err = e.Clone().AppendInfo("traceId", sc.TraceID().String())
+		}
+	}
+
+	errs.ResponseError(c, err)
+}
+
+// message returns the message of the error for the span status. The
+// message of an *errs.Error is the rendered one, without the code prefix of
+// Error, so lazy and interpolated messages are resolved.
+func message(err error) string {
+	if e, ok := errs.AsError(err); ok {
+		return strings.TrimPrefix(e.Error(), "["+e.Code.String()+"] ")
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,131 @@
+package errsotel_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+	"github.com/thirathawat/errs/errsotel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestResponseErrorCtx(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/traced", func(c *gin.Context) {
+		errsotel.ResponseErrorCtx(c, errs.New(errs.CodeNotFound, "User not found"))
+	})
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	w := performRequest(ctx, router, "/traced")
+	span.End()
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "User not found", spans[0].Status().Description)
+		if assert.Len(t, spans[0].Events(), 1) {
+			assert.Equal(t, "exception", spans[0].Events()[0].Name)
+		}
+	}
+}
+
+func TestResponseErrorCtxLazyMessage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/traced", func(c *gin.Context) {
+		errsotel.ResponseErrorCtx(c, errs.NewLazy(errs.CodeNotFound, func() string { return "User 42 not found" }))
+	})
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	performRequest(ctx, router, "/traced")
+	span.End()
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "User 42 not found", spans[0].Status().Description)
+	}
+}
+
+func TestResponseErrorCtxWithTraceID(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/trace-id", func(c *gin.Context) {
+		errsotel.ResponseErrorCtx(c, errs.NotFound)
+	})
+
+	w := performRequest(ctx, router, "/trace-id")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Nil(t, errs.NotFound.Info)
+}
+
+func TestResponseErrorCtxWithTraceIDResolved(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/batch", func(c *gin.Context) {
+		errsotel.ResponseErrorCtx(c, errs.Join(errs.NotFound, errs.ServiceUnavailable))
+	})
+	router.GET("/mapped", func(c *gin.Context) {
+		errsotel.ResponseErrorCtx(c, sql.ErrNoRows)
+	})
+
+	w := performRequest(ctx, router, "/batch")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"errors":[`)
+	assert.Contains(t, w.Body.String(), `"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+
+	w = performRequest(ctx, router, "/mapped")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+}
+
+func TestResponseErrorCtxWithoutSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/untraced", func(c *gin.Context) {
+		errsotel.ResponseErrorCtx(c, errors.New("boom"))
+	})
+
+	w := performRequest(context.Background(), router, "/untraced")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "traceId")
+}
+
+func performRequest(ctx context.Context, router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,70 @@
+// Package errstest provides helpers for asserting on errs error responses in
+// handler tests. It only depends on the standard library.
+package errstest
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thirathawat/errs"
+)
+
+// AssertCode decodes the JSON error response body and reports a test error
+// unless its code is want. Flat and nested envelopes with the default keys
+// are supported. It reports whether the assertion succeeded.
+//
+//	w := httptest.NewRecorder()
+//	router.ServeHTTP(w, req)
+//	errstest.AssertCode(t, w.Body.Bytes(), errs.CodeNotFound)
+func AssertCode(t testing.TB, body []byte, want errs.Code) bool {
+	t.Helper()
+
+	got, err := decodeCode(body)
+	if err != nil {
+		t.Errorf("errstest: cannot decode error response %q: %v", body, err)
+		return false
+	}
+
+	if got != want {
+		t.Errorf("errstest: error code is %s, want %s", got, want)
+		return false
+	}
+
+	return true
+}
+
+// AssertStatus reports a test error unless the status of the recorded
+// response is want. The body is included in the error message. It reports
+// whether the assertion succeeded.
+func AssertStatus(t testing.TB, w *httptest.ResponseRecorder, want int) bool {
+	t.Helper()
+
+	if w.Code != want {
+		t.Errorf("errstest: status is %d, want %d; body: %s", w.Code, want, w.Body.Bytes())
+		return false
+	}
+
+	return true
+}
+
+// decodeCode returns the code of the JSON error response body, unwrapping
+// the "error" object of a nested envelope.
+func decodeCode(body []byte) (errs.Code, error) {
+	var v struct {
+		Code  errs.Code `json:"code"`
+		Error *struct {
+			Code errs.Code `json:"code"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", err
+	}
+
+	if v.Error != nil {
+		return v.Error.Code, nil
+	}
+
+	return v.Code, nil
+}
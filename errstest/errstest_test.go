@@ -0,0 +1,70 @@
+package errstest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thirathawat/errs"
+	"github.com/thirathawat/errs/errstest"
+)
+
+// recordingTB records the errors reported by the helpers.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCode(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want errs.Code
+		ok   bool
+	}{
+		{"flat", `{"code":"NOT_FOUND","message":"User not found"}`, errs.CodeNotFound, true},
+		{"nested", `{"error":{"code":"NOT_FOUND","message":"User not found"}}`, errs.CodeNotFound, true},
+		{"mismatch", `{"code":"CONFLICT","message":"Order already exists"}`, errs.CodeNotFound, false},
+		{"not json", `Internal Server Error`, errs.CodeInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recordingTB{TB: t}
+			if got := errstest.AssertCode(r, []byte(tt.body), tt.want); got != tt.ok {
+				t.Errorf("AssertCode() = %v, want %v", got, tt.ok)
+			}
+
+			if tt.ok != (len(r.errors) == 0) {
+				t.Errorf("reported errors %q", r.errors)
+			}
+		})
+	}
+}
+
+func TestAssertStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/users/1", nil), errs.New(errs.CodeNotFound, "User not found"))
+
+	r := &recordingTB{TB: t}
+	if !errstest.AssertStatus(r, w, http.StatusNotFound) || len(r.errors) != 0 {
+		t.Errorf("AssertStatus(404) reported %q", r.errors)
+	}
+
+	if errstest.AssertStatus(r, w, http.StatusOK) || len(r.errors) != 1 {
+		t.Fatalf("AssertStatus(200) reported %q", r.errors)
+	}
+
+	want := `errstest: status is 404, want 200; body: {"code":"NOT_FOUND"`
+	if got := r.errors[0]; !strings.HasPrefix(got, want) {
+		t.Errorf("error message is %q, want prefix %q", got, want)
+	}
+}
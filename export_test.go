@@ -0,0 +1,50 @@
+package errs
+
+// ResetErrorHooks removes the hooks registered with OnError.
+func ResetErrorHooks() {
+	errorHooksMu.Lock()
+	defer errorHooksMu.Unlock()
+
+	errorHooks = nil
+}
+
+// ResetResponseHooks removes the hooks registered with OnResponse.
+func ResetResponseHooks() {
+	responseHooksMu.Lock()
+	defer responseHooksMu.Unlock()
+
+	responseHooks = nil
+}
+
+// ResetRedactedKeys removes the keys registered with RegisterRedactedKeys.
+func ResetRedactedKeys() {
+	redactedKeysMu.Lock()
+	defer redactedKeysMu.Unlock()
+
+	redactedKeys = make(map[string]struct{})
+}
+
+// ResetCodes removes the codes registered with RegisterCode.
+func ResetCodes() {
+	codeSpecsMu.Lock()
+	defer codeSpecsMu.Unlock()
+
+	codeSpecs = builtinSpecs()
+}
+
+// ResetDuplicateKeyMatchers removes the matchers registered with
+// RegisterDuplicateKeyMatcher.
+func ResetDuplicateKeyMatchers() {
+	duplicateKeyMatchersMu.Lock()
+	defer duplicateKeyMatchersMu.Unlock()
+
+	duplicateKeyMatchers = nil
+}
+
+// CurrentLogger returns the logger set with SetLogger.
+func CurrentLogger() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+
+	return logger
+}
@@ -0,0 +1,77 @@
+package errs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// LogFields returns the code, message, timestamp and info of the error as a
+// map for structured loggers and custom renderers. The message is the public
+// message when set, and values of keys registered with RegisterRedactedKeys
+// are redacted. The info key is left out when there is no info.
+func (e *Error) LogFields() map[string]interface{} {
+	result := map[string]interface{}{
+		"code":      e.Code,
+		"message":   e.publicMessage(),
+		"timestamp": e.Timestamp,
+	}
+
+	if info := e.redactedInfo(); len(info) > 0 {
+		result["info"] = info
+	}
+
+	return result
+}
+
+// FlatFields returns the error as a flat map of string values for log
+// backends that do not handle nested objects. Nested info is flattened into
+// dotted keys such as "info.address.zip", and every key is prefixed with
+// prefix followed by a dot when prefix is not empty. Values of keys
+// registered with RegisterRedactedKeys are redacted.
+func (e *Error) FlatFields(prefix string) map[string]string {
+	result := map[string]string{
+		flatKey(prefix, "code"):      e.Code.String(),
+		flatKey(prefix, "message"):   e.message(),
+		flatKey(prefix, "timestamp"): e.Timestamp.Format(time.RFC3339Nano),
+	}
+
+	for k, v := range e.redactedInfo() {
+		flatten(result, flatKey(flatKey(prefix, "info"), k), v)
+	}
+
+	return result
+}
+
+// flatten adds v to result under key, descending into maps and slices.
+func flatten(result map[string]string, key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, v := range v {
+			flatten(result, flatKey(key, k), v)
+		}
+	case map[string]string:
+		for k, v := range v {
+			result[flatKey(key, k)] = v
+		}
+	case []interface{}:
+		for i, v := range v {
+			flatten(result, flatKey(key, strconv.Itoa(i)), v)
+		}
+	case []string:
+		for i, v := range v {
+			result[flatKey(key, strconv.Itoa(i))] = v
+		}
+	default:
+		result[key] = fmt.Sprint(v)
+	}
+}
+
+// flatKey joins the prefix and the key with a dot.
+func flatKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
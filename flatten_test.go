@@ -0,0 +1,87 @@
+package errs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestFlatFields(t *testing.T) {
+	err := errs.New(errs.CodeBadRequest, "Bad request", errs.WithInfo(map[string]interface{}{
+		"userId": 42,
+		"address": map[string]interface{}{
+			"zip":  "10110",
+			"geo":  map[string]interface{}{"lat": 13.7},
+			"tags": []string{"home", "billing"},
+		},
+	}))
+
+	assert.Equal(t, map[string]string{
+		"code":                 "BAD_REQUEST",
+		"message":              "Bad request",
+		"timestamp":            err.Timestamp.Format(time.RFC3339Nano),
+		"info.userId":          "42",
+		"info.address.zip":     "10110",
+		"info.address.geo.lat": "13.7",
+		"info.address.tags.0":  "home",
+		"info.address.tags.1":  "billing",
+	}, err.FlatFields(""))
+}
+
+func TestFlatFieldsWithPrefix(t *testing.T) {
+	err := errs.New(errs.CodeNotFound, "Not found", errs.WithInfo(map[string]interface{}{
+		"address": map[string]interface{}{"zip": "10110"},
+	}))
+
+	fields := err.FlatFields("error")
+	assert.Equal(t, "NOT_FOUND", fields["error.code"])
+	assert.Equal(t, "Not found", fields["error.message"])
+	assert.Equal(t, "10110", fields["error.info.address.zip"])
+	assert.Len(t, fields, 4)
+}
+
+func TestFlatFieldsRedacted(t *testing.T) {
+	t.Cleanup(errs.ResetRedactedKeys)
+	errs.RegisterRedactedKeys("password")
+
+	err := errs.New(errs.CodeBadRequest, "Invalid login", errs.WithInfo(map[string]interface{}{
+		"password": "hunter2",
+		"form":     map[string]interface{}{"password": "hunter2", "user": "alice"},
+	}))
+
+	fields := err.FlatFields("")
+	assert.Equal(t, "[REDACTED]", fields["info.password"])
+	assert.Equal(t, "[REDACTED]", fields["info.form.password"])
+	assert.Equal(t, "alice", fields["info.form.user"])
+	assert.Equal(t, "hunter2", err.Info["password"])
+}
+
+func TestLogFields(t *testing.T) {
+	errs.RegisterRedactedKeys("password")
+	t.Cleanup(errs.ResetRedactedKeys)
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := errs.New(errs.CodeUnauthorized, "password mismatch for alice",
+		errs.WithPublicMessage("Invalid credentials"),
+		errs.WithTimestamp(ts),
+		errs.WithInfo(map[string]interface{}{
+			"user":    "alice",
+			"request": map[string]interface{}{"password": "hunter2", "remember": true},
+		}),
+	)
+
+	assert.Equal(t, map[string]interface{}{
+		"code":      errs.CodeUnauthorized,
+		"message":   "Invalid credentials",
+		"timestamp": ts,
+		"info": map[string]interface{}{
+			"user":    "alice",
+			"request": map[string]interface{}{"password": "[REDACTED]", "remember": true},
+		},
+	}, err.LogFields())
+
+	fields := errs.New(errs.CodeNotFound, "User not found", errs.WithTimestamp(ts)).LogFields()
+	assert.Equal(t, map[string]interface{}{"code": errs.CodeNotFound, "message": "User not found", "timestamp": ts}, fields)
+}
@@ -0,0 +1,68 @@
+package errs
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Go runs the functions concurrently, at most GOMAXPROCS at a time, and
+// waits for all of them. The first error returned is converted with MapError;
+// once a function fails, the functions not yet started are skipped. It
+// returns nil if every function succeeds.
+//
+//	if err := errs.Go(loadUser, loadOrders); err != nil {
+//		errs.ResponseError(c, err)
+//		return
+//	}
+func Go(fns ...func() error) *Error {
+	return GoLimit(runtime.GOMAXPROCS(0), fns...)
+}
+
+// GoLimit is like Go but runs at most limit functions at a time. A limit
+// below 1 runs them one at a time.
+func GoLimit(limit int, fns ...func() error) *Error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		first error
+	)
+
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return first != nil
+	}
+
+	sem := make(chan struct{}, limit)
+	for _, fn := range fns {
+		sem <- struct{}{}
+		if failed() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(fn func() error) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(); err != nil {
+				mu.Lock()
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+			}
+		}(fn)
+	}
+
+	wg.Wait()
+	return MapError(first)
+}
@@ -0,0 +1,57 @@
+package errs_test
+
+import (
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestGo(t *testing.T) {
+	var calls int32
+	fn := func() error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}
+
+	assert.Nil(t, errs.Go(fn, fn, fn))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Nil(t, errs.Go())
+}
+
+func TestGoSingleFailure(t *testing.T) {
+	err := errs.Go(
+		func() error { return nil },
+		func() error { return sql.ErrNoRows },
+		func() error { return nil },
+	)
+
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errs.CodeNotFound, err.Code)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
+}
+
+func TestGoLimitMultipleFailures(t *testing.T) {
+	var calls int32
+	fail := func(err error) func() error {
+		return func() error {
+			atomic.AddInt32(&calls, 1)
+			return err
+		}
+	}
+
+	first := errs.New(errs.CodeConflict, "Order already exists")
+	err := errs.GoLimit(1,
+		fail(nil),
+		fail(first),
+		fail(errors.New("boom")),
+		fail(nil),
+	)
+
+	assert.Same(t, first, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
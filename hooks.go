@@ -0,0 +1,93 @@
+package errs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorHooks holds the callbacks registered with OnError.
+var (
+	errorHooksMu sync.RWMutex
+	errorHooks   []func(*Error)
+)
+
+// responseHooks holds the callbacks registered with OnResponse.
+var (
+	responseHooksMu sync.RWMutex
+	responseHooks   []func(*gin.Context, *Error)
+)
+
+// OnError registers fn to be called synchronously by New after each error
+// is constructed, e.g. to count errors by code. Hooks run in registration
+// order; a panicking hook is recovered and logged.
+func OnError(fn func(*Error)) {
+	errorHooksMu.Lock()
+	defer errorHooksMu.Unlock()
+
+	errorHooks = append(errorHooks, fn)
+}
+
+// runErrorHooks calls the registered hooks with the error.
+func runErrorHooks(e *Error) {
+	errorHooksMu.RLock()
+	hooks := errorHooks
+	errorHooksMu.RUnlock()
+
+	for _, fn := range hooks {
+		runErrorHook(fn, e)
+	}
+}
+
+// runErrorHook calls fn with the error, recovering from a panic.
+func runErrorHook(fn func(*Error), e *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log(context.Background(), SeverityError, "errs: error hook panicked", map[string]interface{}{
+				"panic": r,
+				"code":  e.Code,
+			})
+		}
+	}()
+
+	fn(e)
+}
+
+// OnResponse registers fn to be called by ResponseError right before the
+// error response is written, e.g. to record metrics or set headers. The
+// error is the one written, with the request ID; unknown errors are passed
+// as a CodeInternalServerError wrapping them. Hooks run in registration
+// order; a panicking hook is recovered and logged.
+func OnResponse(fn func(*gin.Context, *Error)) {
+	responseHooksMu.Lock()
+	defer responseHooksMu.Unlock()
+
+	responseHooks = append(responseHooks, fn)
+}
+
+// runResponseHooks calls the registered response hooks.
+func runResponseHooks(c *gin.Context, e *Error) {
+	responseHooksMu.RLock()
+	hooks := responseHooks
+	responseHooksMu.RUnlock()
+
+	for _, fn := range hooks {
+		runResponseHook(fn, c, e)
+	}
+}
+
+// runResponseHook calls fn with the context and the error, recovering from
+// a panic.
+func runResponseHook(fn func(*gin.Context, *Error), c *gin.Context, e *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log(requestContext(c), SeverityError, "errs: response hook panicked", map[string]interface{}{
+				"panic": r,
+				"code":  e.Code,
+			})
+		}
+	}()
+
+	fn(c, e)
+}
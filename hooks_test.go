@@ -0,0 +1,101 @@
+package errs_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestOnError(t *testing.T) {
+	t.Cleanup(errs.ResetErrorHooks)
+
+	var codes []errs.Code
+	errs.OnError(func(e *errs.Error) {
+		codes = append(codes, e.Code)
+	})
+
+	err := errs.New(errs.CodeConflict, "Email already taken")
+	errs.New(errs.CodeNotFound, "User not found")
+
+	assert.Equal(t, []errs.Code{errs.CodeConflict, errs.CodeNotFound}, codes)
+	assert.Equal(t, "Email already taken", err.Message)
+}
+
+func TestOnErrorRecoversPanic(t *testing.T) {
+	t.Cleanup(errs.ResetErrorHooks)
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	var called bool
+	errs.OnError(func(*errs.Error) { panic("boom") })
+	errs.OnError(func(*errs.Error) { called = true })
+
+	var err *errs.Error
+	assert.NotPanics(t, func() {
+		err = errs.New(errs.CodeInternalServerError, "")
+	})
+	assert.NotNil(t, err)
+	assert.True(t, called)
+
+	entry := lastEntry(l)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "boom", entry.Fields["panic"])
+	}
+}
+
+func TestOnResponse(t *testing.T) {
+	t.Cleanup(errs.ResetResponseHooks)
+
+	var got []string
+	errs.OnResponse(func(c *gin.Context, e *errs.Error) {
+		got = append(got, c.FullPath()+" "+e.Code.String())
+		c.Header("X-Error-Seen", "1")
+	})
+	errs.OnResponse(func(c *gin.Context, e *errs.Error) {
+		got = append(got, "second")
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "User not found"))
+	})
+	router.GET("/boom", func(c *gin.Context) {
+		errs.ResponseError(c, errors.New("boom"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, "1", w.Header().Get("X-Error-Seen"))
+
+	performRequest(router, http.MethodGet, "/boom", nil)
+	assert.Equal(t, []string{"/users/:id NOT_FOUND", "second", "/boom INTERNAL_SERVER_ERROR", "second"}, got)
+}
+
+func TestOnResponseRecoversPanic(t *testing.T) {
+	t.Cleanup(errs.ResetResponseHooks)
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.OnResponse(func(*gin.Context, *errs.Error) { panic("boom") })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "User not found"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	entry := lastEntry(l)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "errs: response hook panicked", entry.Message)
+		assert.Equal(t, "boom", entry.Fields["panic"])
+	}
+}
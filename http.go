@@ -0,0 +1,130 @@
+package errs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// WriteError is the net/http counterpart of ResponseError. It writes err as
+// the JSON envelope with the matching status. The request ID is taken from
+// the request context, see ContextWithRequestID.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	resp := NewResponse(err, RequestIDFromContext(r.Context()))
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+
+	w.WriteHeader(resp.Status)
+	if _, err := w.Write(resp.Body); err != nil {
+		log(r.Context(), SeverityError, "errs: failed to write response", map[string]interface{}{"error": err})
+	}
+}
+
+// Response is a JSON error response, for frameworks other than gin and
+// net/http.
+type Response struct {
+	// Status is the HTTP status code.
+	Status int
+
+	// Header holds the response headers, including the Content-Type.
+	Header http.Header
+
+	// Body is the JSON body, following the envelope settings.
+	Body []byte
+}
+
+// NewResponse returns the JSON response for err like WriteError writes it,
+// with the request ID unless it is empty. Errors are resolved with Resolve.
+func NewResponse(err error, requestID string) Response {
+	resp := Response{
+		Status: http.StatusInternalServerError,
+		Header: make(http.Header),
+	}
+
+	if requestID != "" {
+		resp.Header.Set("X-Request-ID", requestID)
+	}
+
+	var body interface{} = fallback(fallbackMessage(requestID))
+	if e, ok := responseErr(err, requestID); ok {
+		for k, v := range e.headers {
+			resp.Header.Set(k, v)
+		}
+
+		if header := currentConfig().codeHeader; header != "" {
+			resp.Header.Set(header, e.Code.String())
+		}
+
+		body, resp.Status = exposed(e), e.HTTPStatusCode()
+	}
+
+	conf := currentConfig()
+	b, err := marshalBody(body, conf.pretty)
+	if err != nil {
+		log(context.Background(), SeverityError, "errs: failed to marshal response", map[string]interface{}{"error": err})
+		resp.Status = http.StatusInternalServerError
+		b, _ = marshalBody(fallback(fallbackMessage(requestID)), conf.pretty)
+	}
+
+	resp.Header.Set("Content-Type", conf.contentType)
+	resp.Body = b
+	return resp
+}
+
+// Recover is a net/http middleware that recovers panics and writes a 500
+// error with WriteError. The panic and its stack are logged with the
+// configured logger. The error passed to hooks carries the recovered value
+// in Info["panic"]; the response body does not.
+//
+//	r := chi.NewRouter()
+//	r.Use(errs.Recover)
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			logPanic(r.Context(), rec)
+			WriteError(w, r, panicError(r.Context(), rec))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logPanic logs the recovered value and the stack.
+func logPanic(ctx context.Context, rec interface{}) {
+	log(ctx, SeverityError, "errs: recovered panic", map[string]interface{}{
+		"panic": fmt.Sprint(rec),
+		"stack": string(debug.Stack()),
+	})
+}
+
+// panicError returns the 500 error written for a recovered panic. The error
+// passed to hooks carries the recovered value in Info["panic"]; the returned
+// copy does not.
+func panicError(ctx context.Context, rec interface{}) *Error {
+	e := NewCtx(ctx, CodeInternalServerError, "", WithInfo(map[string]interface{}{"panic": rec}))
+	body := e.Clone()
+	delete(body.Info, "panic")
+	return body
+}
+
+// marshalBody returns the JSON response body for v, indented when pretty is
+// set like gin's IndentedJSON.
+func marshalBody(v interface{}, pretty bool) ([]byte, error) {
+	if pretty {
+		return json.MarshalIndent(jsonBody(v), "", "    ")
+	}
+
+	return json.Marshal(jsonBody(v))
+}
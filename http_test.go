@@ -0,0 +1,94 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestWriteError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r = r.WithContext(errs.ContextWithRequestID(r.Context(), "req-1"))
+	w := httptest.NewRecorder()
+
+	errs.WriteError(w, r, errs.New(errs.CodeNotFound, "User not found"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "NOT_FOUND", body["code"])
+	assert.Equal(t, "User not found", body["message"])
+	assert.Equal(t, map[string]interface{}{"requestID": "req-1"}, body["info"])
+}
+
+func TestWriteErrorNonErr(t *testing.T) {
+	w := httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/", nil), errors.New("boom"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `"Internal Server Error"`, w.Body.String())
+}
+
+func TestWriteErrorWithHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := errs.New(errs.CodeConflict, "Order already exists", errs.WithHeaders(map[string]string{"Location": "/orders/42"}))
+	errs.WriteError(w, httptest.NewRequest(http.MethodPost, "/orders", nil), err)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "/orders/42", w.Header().Get("Location"))
+}
+
+func TestRecover(t *testing.T) {
+	t.Cleanup(errs.ResetErrorHooks)
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	var hooked *errs.Error
+	errs.OnError(func(e *errs.Error) { hooked = e })
+
+	h := errs.Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("nil map write")
+	}))
+
+	w := httptest.NewRecorder()
+	assert.NotPanics(t, func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "INTERNAL_SERVER_ERROR", body["code"])
+	assert.Equal(t, "Internal Server Error", body["message"])
+	assert.NotContains(t, body, "info")
+
+	if assert.NotNil(t, hooked) {
+		assert.Equal(t, "nil map write", hooked.Info["panic"])
+	}
+
+	if assert.Len(t, l.entries, 1) {
+		assert.Equal(t, errs.SeverityError, l.entries[0].severity)
+		assert.Equal(t, "nil map write", l.entries[0].fields["panic"])
+		assert.Contains(t, l.entries[0].fields["stack"], "TestRecover")
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	h := errs.Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,69 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithInterpolation replaces the {key} placeholders of the message and the
+// public message with the info values of the keys when the error is printed,
+// marshaled or logged. Placeholders of missing keys are left intact, and
+// values of redacted keys are replaced with "[REDACTED]". Braces are escaped
+// by doubling them: "{{" and "}}" are written as "{" and "}".
+//
+//	errs.New(errs.CodeNotFound, "user {userId} not found",
+//		errs.WithInfo(map[string]interface{}{"userId": 42}),
+//		errs.WithInterpolation(),
+//	)
+func WithInterpolation() Option {
+	return func(o *option) {
+		o.interpolate = true
+	}
+}
+
+// message returns the message of the error, interpolated when enabled with
+// WithInterpolation.
+func (e *Error) message() string {
+	return e.interpolated(e.baseMessage())
+}
+
+// interpolated returns msg with the placeholders replaced when enabled with
+// WithInterpolation, and msg otherwise.
+func (e *Error) interpolated(msg string) string {
+	if !e.interpolate || !strings.ContainsAny(msg, "{}") {
+		return msg
+	}
+
+	info := e.info()
+	var b strings.Builder
+	b.Grow(len(msg))
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		switch {
+		case (c == '{' || c == '}') && i+1 < len(msg) && msg[i+1] == c:
+			b.WriteByte(c)
+			i++
+		case c == '{':
+			end := strings.IndexAny(msg[i+1:], "{}")
+			if end < 0 || msg[i+1+end] != '}' {
+				b.WriteByte(c)
+				continue
+			}
+
+			key := msg[i+1 : i+1+end]
+			v, ok := info[key]
+			if !ok {
+				b.WriteString(msg[i : i+end+2])
+			} else if isRedacted(key) {
+				b.WriteString(redacted)
+			} else {
+				fmt.Fprintf(&b, "%v", v)
+			}
+			i += end + 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
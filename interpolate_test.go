@@ -0,0 +1,52 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestWithInterpolation(t *testing.T) {
+	errs.RegisterRedactedKeys("email")
+	t.Cleanup(errs.ResetRedactedKeys)
+
+	info := map[string]interface{}{"userId": 42, "region": "eu", "email": "alice@example.com"}
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"user {userId} not found in {region}", "user 42 not found in eu"},
+		{"user {userId} not found in {zone}", "user 42 not found in {zone}"},
+		{"no account for {email}", "no account for [REDACTED]"},
+		{"use {{userId}} for {userId}", "use {userId} for 42"},
+		{"unclosed {userId", "unclosed {userId"},
+		{"nested {{user{userId}}}", "nested {user42}"},
+		{"stray } and {", "stray } and {"},
+		{"no placeholders", "no placeholders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := errs.New(errs.CodeNotFound, tt.msg, errs.WithInfo(info), errs.WithInterpolation())
+			assert.Equal(t, "[NOT_FOUND] "+tt.want, err.Error())
+			assert.Equal(t, tt.msg, err.Message)
+		})
+	}
+}
+
+func TestWithInterpolationMarshaling(t *testing.T) {
+	err := errs.New(errs.CodeNotFound, "user {userId} not found",
+		errs.WithInfo(map[string]interface{}{"userId": 42}),
+		errs.WithPublicMessage("no user {userId}"),
+		errs.WithInterpolation(),
+	)
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"message":"no user 42"`)
+
+	err = errs.New(errs.CodeNotFound, "user {userId} not found", errs.WithInfo(map[string]interface{}{"userId": 42}))
+	assert.Equal(t, "[NOT_FOUND] user {userId} not found", err.Error())
+}
@@ -0,0 +1,66 @@
+//go:build go1.20
+
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestResponseErrorWithErrorsJoin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	err := errors.Join(
+		errs.New(errs.CodeNotFound, "Item 1 not found"),
+		errors.New("cache miss"),
+		fmt.Errorf("item 2: %w", errs.New(errs.CodeServiceUnavailable, "Inventory unavailable")),
+	)
+	router.GET("/batch", func(c *gin.Context) {
+		errs.ResponseError(c, err)
+	})
+
+	w := performRequest(router, http.MethodGet, "/batch", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var body struct {
+		Code errs.Code `json:"code"`
+		Info struct {
+			Errors []struct {
+				Code errs.Code `json:"code"`
+			} `json:"errors"`
+		} `json:"info"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, errs.CodeServiceUnavailable, body.Code)
+	if assert.Len(t, body.Info.Errors, 2) {
+		assert.Equal(t, errs.CodeNotFound, body.Info.Errors[0].Code)
+		assert.Equal(t, errs.CodeServiceUnavailable, body.Info.Errors[1].Code)
+	}
+}
+
+func TestResponseErrorWithErrorsJoinSingle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/single", func(c *gin.Context) {
+		errs.ResponseError(c, errors.Join(errors.New("cache miss"), errs.New(errs.CodeConflict, "Item exists")))
+	})
+	router.GET("/plain", func(c *gin.Context) {
+		errs.ResponseError(c, errors.Join(errors.New("cache miss"), errors.New("retry")))
+	})
+
+	w := performRequest(router, http.MethodGet, "/single", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.NotContains(t, w.Body.String(), `"errors"`)
+
+	w = performRequest(router, http.MethodGet, "/plain", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,64 @@
+package errs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonAPIContentType is the media type of JSON:API documents.
+const jsonAPIContentType = "application/vnd.api+json"
+
+// jsonAPIError is an error object of a JSON:API document.
+type jsonAPIError struct {
+	Status string                 `json:"status"`
+	Code   string                 `json:"code"`
+	Title  string                 `json:"title"`
+	Detail string                 `json:"detail"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// ResponseJSONAPI returns an error response in the JSON:API format, for
+// frontends consuming JSON:API:
+//
+//	{"errors":[{"status":"404","code":"NOT_FOUND","title":"Not Found","detail":"User not found","meta":{"userId":42}}]}
+//
+// The status is the HTTP status code as a string, the title its status text,
+// the detail the public message and the meta the info, redacted like in
+// ResponseError. The error is resolved like ResponseError does, and unknown
+// errors are written as a 500 with the fallback message. The Content-Type is
+// application/vnd.api+json, whatever the client accepts.
+func ResponseJSONAPI(c *gin.Context, err error) {
+	respond(c, err, func(status int, e *Error, text string) {
+		obj := jsonAPIError{
+			Status: strconv.Itoa(status),
+			Code:   CodeInternalServerError.String(),
+			Title:  http.StatusText(status),
+			Detail: text,
+		}
+
+		if e != nil {
+			e = exposed(e)
+			obj.Code = e.Code.String()
+			obj.Detail = e.publicMessage()
+			obj.Meta = e.redactedInfo()
+		}
+
+		b, err := marshalJSONAPI(obj)
+		if err != nil {
+			obj.Meta = sanitizeInfo(e.Code, obj.Meta)
+			b, _ = marshalJSONAPI(obj)
+		}
+
+		c.Data(status, jsonAPIContentType, b)
+	})
+}
+
+// marshalJSONAPI encodes the JSON:API document of obj.
+func marshalJSONAPI(obj jsonAPIError) ([]byte, error) {
+	return json.Marshal(struct {
+		Errors []jsonAPIError `json:"errors"`
+	}{[]jsonAPIError{obj}})
+}
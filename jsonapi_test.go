@@ -0,0 +1,52 @@
+package errs_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestResponseJSONAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/jsonapi", func(c *gin.Context) {
+		errs.ResponseJSONAPI(c, errs.New(errs.CodeNotFound, "User not found", errs.WithInfo(map[string]interface{}{
+			"userId": 42,
+		})))
+	})
+
+	w := performRequest(router, http.MethodGet, "/jsonapi", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/vnd.api+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"errors":[{
+		"status": "404",
+		"code": "NOT_FOUND",
+		"title": "Not Found",
+		"detail": "User not found",
+		"meta": {"userId": 42}
+	}]}`, w.Body.String())
+}
+
+func TestResponseJSONAPIUnknownError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/jsonapi", func(c *gin.Context) {
+		errs.ResponseJSONAPI(c, errors.New("boom"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/jsonapi", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/vnd.api+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"errors":[{
+		"status": "500",
+		"code": "INTERNAL_SERVER_ERROR",
+		"title": "Internal Server Error",
+		"detail": "Internal Server Error"
+	}]}`, w.Body.String())
+}
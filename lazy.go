@@ -0,0 +1,88 @@
+package errs
+
+import "sync"
+
+// lazyMessage is a message evaluated on first use.
+type lazyMessage struct {
+	once sync.Once
+	fn   func() string
+	msg  string
+}
+
+// NewLazy returns a new error like New whose message is built by msgFn on
+// first use, by Error, marshaling or logging, and then cached. It avoids the
+// cost of formatting messages of errors that are never rendered. The Message
+// field stays empty; if msgFn returns an empty message, a default derived
+// from the code is used.
+func NewLazy(code Code, msgFn func() string, opts ...Option) *Error {
+	return New(code, "", append(opts, func(o *option) {
+		o.lazyMessage = msgFn
+	})...)
+}
+
+// baseMessage returns the message of the error, evaluating the lazy message
+// unless Message is set.
+func (e *Error) baseMessage() string {
+	if e.lazy == nil || e.Message != "" {
+		return e.Message
+	}
+
+	e.lazy.once.Do(func() {
+		e.lazy.msg = e.lazy.fn()
+		if e.lazy.msg == "" {
+			e.lazy.msg = defaultMessage(e.Code)
+		}
+	})
+
+	return e.lazy.msg
+}
+
+// lazyInfo is an info map computed on first use.
+type lazyInfo struct {
+	once sync.Once
+	fn   func() map[string]interface{}
+	info map[string]interface{}
+}
+
+// WithInfoFunc sets a function computing info that is expensive to build,
+// such as related IDs loaded from a database. It is called at most once,
+// when the error is first marshaled, logged or interpolated, and never for
+// errors that are not rendered. The Info field does not include the
+// computed info, which loses to Info on conflicting keys.
+func WithInfoFunc(fn func() map[string]interface{}) Option {
+	return func(o *option) {
+		o.infoFunc = fn
+	}
+}
+
+// get returns the computed info, calling the function on first use.
+func (l *lazyInfo) get() map[string]interface{} {
+	l.once.Do(func() {
+		l.info = l.fn()
+	})
+
+	return l.info
+}
+
+// info returns the info of the error merged with the info computed by the
+// WithInfoFunc function, if any.
+func (e *Error) info() map[string]interface{} {
+	if e.lazyInfo == nil {
+		return e.Info
+	}
+
+	lazy := e.lazyInfo.get()
+	if len(lazy) == 0 {
+		return e.Info
+	}
+
+	result := make(map[string]interface{}, len(lazy)+len(e.Info))
+	for k, v := range lazy {
+		result[k] = v
+	}
+	for k, v := range e.Info {
+		result[k] = v
+	}
+
+	return result
+}
@@ -0,0 +1,107 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestNewLazy(t *testing.T) {
+	var calls int32
+	err := errs.NewLazy(errs.CodeNotFound, func() string {
+		atomic.AddInt32(&calls, 1)
+		return fmt.Sprintf("user %d not found", 42)
+	})
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "[NOT_FOUND] user 42 not found", err.Error())
+		}()
+	}
+	wg.Wait()
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"message":"user 42 not found"`)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestNewLazyUnused(t *testing.T) {
+	err := errs.NewLazy(errs.CodeNotFound, func() string {
+		t.Fatal("message evaluated")
+		return ""
+	}, errs.WithInfo(map[string]interface{}{"userId": 42}))
+
+	assert.Equal(t, errs.CodeNotFound, err.Code)
+	assert.Equal(t, 42, err.Info["userId"])
+}
+
+func TestNewLazyDefaultMessage(t *testing.T) {
+	err := errs.NewLazy(errs.CodeConflict, func() string { return "" })
+	assert.Equal(t, "[CONFLICT] Conflict", err.Error())
+}
+
+func TestNewLazyLogged(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.NewLazy(errs.CodeInternalServerError, func() string { return "db is down" }, errs.WithLogErr(assert.AnError))
+	if entries := l.Entries(); assert.Len(t, entries, 1) {
+		assert.Equal(t, "db is down", entries[0].Message)
+	}
+}
+
+func TestWithInfoFunc(t *testing.T) {
+	var calls int32
+	err := errs.New(errs.CodeNotFound, "Order not found",
+		errs.WithInfo(map[string]interface{}{"orderId": 7, "source": "api"}),
+		errs.WithInfoFunc(func() map[string]interface{} {
+			atomic.AddInt32(&calls, 1)
+			return map[string]interface{}{"relatedIds": []int{1, 2}, "source": "db"}
+		}),
+	)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	assert.Equal(t, map[string]interface{}{"orderId": 7, "source": "api"}, err.Info)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, jsonErr := json.Marshal(err)
+			assert.NoError(t, jsonErr)
+			assert.Contains(t, string(body), `"info":{"orderId":7,"relatedIds":[1,2],"source":"api"}`)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestWithInfoFuncLogged(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.New(errs.CodeNotFound, "Order not found",
+		errs.WithLogErr(errors.New("no rows")),
+		errs.WithInfoFunc(func() map[string]interface{} {
+			return map[string]interface{}{"relatedIds": []int{1, 2}}
+		}),
+	)
+
+	if entry := lastEntry(l); assert.NotNil(t, entry) {
+		assert.Equal(t, map[string]interface{}{"relatedIds": []int{1, 2}}, entry.Fields["info"])
+	}
+}
@@ -0,0 +1,128 @@
+package errs
+
+import (
+	"context"
+	"sync"
+)
+
+// Severity represents the severity of an error.
+type Severity int
+
+// Severity levels.
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+)
+
+// String returns the string representation of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarn:
+		return "warn"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultSeverity returns the severity for the HTTP status code: error for
+// server errors, the client error severity for 4xx, and warn otherwise.
+func defaultSeverity(status int) Severity {
+	switch {
+	case status >= 500:
+		return SeverityError
+	case status >= 400:
+		return currentConfig().clientErrorSeverity
+	default:
+		return SeverityWarn
+	}
+}
+
+// Logger logs the errors of the package.
+type Logger interface {
+	// Log logs the message at the severity with the structured fields.
+	Log(ctx context.Context, severity Severity, msg string, fields map[string]interface{})
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = NopLogger{}
+)
+
+// SetLogger sets the logger of the package. Nothing is logged by default,
+// and a nil logger restores the NopLogger. The errslogrus package provides a
+// logrus logger:
+//
+//	errs.SetLogger(errslogrus.Logger{})
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger{}
+	}
+
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+
+	logger = l
+}
+
+// log logs the message with the configured logger.
+func log(ctx context.Context, severity Severity, msg string, fields map[string]interface{}) {
+	loggerMu.RLock()
+	l := logger
+	loggerMu.RUnlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l.Log(ctx, severity, msg, fields)
+}
+
+// NopLogger is a Logger that discards everything.
+type NopLogger struct{}
+
+// Log implements Logger.
+func (NopLogger) Log(context.Context, Severity, string, map[string]interface{}) {}
+
+// LogEntry is an entry recorded by TestLogger.
+type LogEntry struct {
+	Severity Severity
+	Message  string
+	Fields   map[string]interface{}
+}
+
+// TestLogger is a Logger that records the entries for tests. The zero value
+// is ready to use and safe for concurrent use.
+//
+//	l := &errs.TestLogger{}
+//	errs.SetLogger(l)
+//	t.Cleanup(func() { errs.SetLogger(nil) })
+type TestLogger struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// Log implements Logger.
+func (l *TestLogger) Log(_ context.Context, severity Severity, msg string, fields map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, LogEntry{Severity: severity, Message: msg, Fields: fields})
+}
+
+// Entries returns a copy of the recorded entries in logging order.
+func (l *TestLogger) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]LogEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
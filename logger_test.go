@@ -0,0 +1,198 @@
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+type entry struct {
+	severity errs.Severity
+	msg      string
+	fields   map[string]interface{}
+}
+
+type recordingLogger struct {
+	entries []entry
+}
+
+func (l *recordingLogger) Log(_ context.Context, severity errs.Severity, msg string, fields map[string]interface{}) {
+	l.entries = append(l.entries, entry{severity, msg, fields})
+}
+
+func TestDefaultSeverity(t *testing.T) {
+	tests := []struct {
+		code errs.Code
+		want errs.Severity
+	}{
+		{errs.CodeBadRequest, errs.SeverityWarn},
+		{errs.CodeNotFound, errs.SeverityWarn},
+		{errs.CodeTooManyRequests, errs.SeverityWarn},
+		{errs.CodeInternalServerError, errs.SeverityError},
+		{errs.CodeServiceUnavailable, errs.SeverityError},
+		{errs.Code("UNKNOWN"), errs.SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, errs.New(tt.code, "").Severity)
+		})
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	err := errs.New(errs.CodeInternalServerError, "", errs.WithSeverity(errs.SeverityInfo))
+	assert.Equal(t, errs.SeverityInfo, err.Severity)
+}
+
+func TestSetLogger(t *testing.T) {
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	logErr := errors.New("user not in cache")
+	errs.New(errs.CodeNotFound, "User not found", errs.WithLogErr(logErr))
+	errs.New(errs.CodeInternalServerError, "Internal server error", errs.WithLogErr(logErr))
+	errs.New(errs.CodeInternalServerError, "Degraded", errs.WithLogErr(logErr), errs.WithSeverity(errs.SeverityDebug))
+	errs.New(errs.CodeInternalServerError, "Not logged")
+
+	if assert.Len(t, l.entries, 3) {
+		assert.Equal(t, entry{errs.SeverityWarn, "User not found", map[string]interface{}{"code": errs.CodeNotFound, "error": logErr}}, l.entries[0])
+		assert.Equal(t, errs.SeverityError, l.entries[1].severity)
+		assert.Equal(t, errs.SeverityDebug, l.entries[2].severity)
+	}
+}
+
+func TestSetAutoLog(t *testing.T) {
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.New(errs.CodeInternalServerError, "Not logged by default")
+	assert.Empty(t, l.entries)
+
+	errs.SetAutoLog(true)
+	t.Cleanup(func() { errs.SetAutoLog(false) })
+
+	errs.New(errs.CodeNotFound, "User not found")
+	errs.New(errs.CodeServiceUnavailable, "Service unavailable")
+	errs.New(errs.CodeInternalServerError, "Degraded", errs.WithSeverity(errs.SeverityInfo))
+	if assert.Len(t, l.entries, 2) {
+		assert.Equal(t, entry{errs.SeverityError, "Service unavailable", map[string]interface{}{"code": errs.CodeServiceUnavailable}}, l.entries[0])
+		assert.Equal(t, errs.SeverityInfo, l.entries[1].severity)
+	}
+}
+
+func TestSetAutoLogThreshold(t *testing.T) {
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.SetAutoLog(true)
+	errs.SetAutoLogThreshold(http.StatusNotFound)
+	t.Cleanup(func() {
+		errs.SetAutoLog(false)
+		errs.SetAutoLogThreshold(http.StatusInternalServerError)
+	})
+
+	errs.New(errs.CodeBadRequest, "Bad request")
+	errs.New(errs.CodeNotFound, "User not found")
+	errs.New(errs.CodeConflict, "Email already taken")
+	if assert.Len(t, l.entries, 2) {
+		assert.Equal(t, "User not found", l.entries[0].msg)
+		assert.Equal(t, errs.SeverityWarn, l.entries[0].severity)
+		assert.Equal(t, "Email already taken", l.entries[1].msg)
+	}
+}
+
+func TestDefaultLoggerIsNop(t *testing.T) {
+	assert.Equal(t, errs.NopLogger{}, errs.CurrentLogger())
+
+	errs.SetLogger(&errs.TestLogger{})
+	errs.SetLogger(nil)
+	assert.Equal(t, errs.NopLogger{}, errs.CurrentLogger())
+
+	assert.NotPanics(t, func() {
+		errs.New(errs.CodeInternalServerError, "Internal server error", errs.WithLogErr(errors.New("db is down")))
+	})
+}
+
+func TestTestLogger(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	logErr := errors.New("user not in cache")
+	errs.New(errs.CodeNotFound, "User not found", errs.WithLogErr(logErr))
+	errs.New(errs.CodeInternalServerError, "Not logged")
+
+	assert.Equal(t, []errs.LogEntry{
+		{Severity: errs.SeverityWarn, Message: "User not found", Fields: map[string]interface{}{"code": errs.CodeNotFound, "error": logErr}},
+	}, l.Entries())
+}
+
+func TestLogFieldsCodeAndInfo(t *testing.T) {
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.RegisterRedactedKeys("password")
+	t.Cleanup(errs.ResetRedactedKeys)
+
+	errs.New(errs.CodeBadRequest, "Invalid login",
+		errs.WithInfo(map[string]interface{}{"user": "alice", "password": "hunter2"}),
+		errs.WithLogErr(errors.New("bad credentials")),
+	)
+
+	if assert.Len(t, l.entries, 1) {
+		assert.Equal(t, errs.CodeBadRequest, l.entries[0].fields["code"])
+		assert.Equal(t, map[string]interface{}{"user": "alice", "password": "[REDACTED]"}, l.entries[0].fields["info"])
+	}
+}
+
+func TestSetClientErrorSeverity(t *testing.T) {
+	l := &recordingLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	errs.SetClientErrorSeverity(errs.SeverityDebug)
+	t.Cleanup(func() { errs.SetClientErrorSeverity(errs.SeverityWarn) })
+
+	logErr := errors.New("lookup failed")
+	tests := []struct {
+		code errs.Code
+		opts []errs.Option
+		want errs.Severity
+	}{
+		{errs.CodeBadRequest, nil, errs.SeverityDebug},
+		{errs.CodeNotFound, nil, errs.SeverityDebug},
+		{errs.CodeClientClosedRequest, nil, errs.SeverityDebug},
+		{errs.CodeInternalServerError, nil, errs.SeverityError},
+		{errs.CodeTimeout, nil, errs.SeverityError},
+		{errs.CodeNotFound, []errs.Option{errs.WithSeverity(errs.SeverityInfo)}, errs.SeverityInfo},
+	}
+
+	for _, tt := range tests {
+		errs.New(tt.code, "", append(tt.opts, errs.WithLogErr(logErr))...)
+	}
+
+	if assert.Len(t, l.entries, len(tests)) {
+		for i, tt := range tests {
+			assert.Equal(t, tt.want, l.entries[i].severity, tt.code)
+		}
+	}
+}
+
+// lastEntry returns the last entry recorded by the logger, or nil.
+func lastEntry(l *errs.TestLogger) *errs.LogEntry {
+	entries := l.Entries()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return &entries[len(entries)-1]
+}
@@ -0,0 +1,134 @@
+package errs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// mapping maps a target error to an error code and message.
+type mapping struct {
+	target error
+	code   Code
+	msg    string
+}
+
+// mappings holds the built-in and registered mappings, in registration
+// order.
+var (
+	mappingsMu sync.RWMutex
+	mappings   = []mapping{
+		{target: sql.ErrNoRows, code: CodeNotFound},
+		{target: context.DeadlineExceeded, code: CodeTimeout},
+		{target: context.Canceled, code: CodeClientClosedRequest},
+	}
+)
+
+// duplicateKeyMatchers holds the matchers registered with
+// RegisterDuplicateKeyMatcher, in registration order.
+var (
+	duplicateKeyMatchersMu sync.RWMutex
+	duplicateKeyMatchers   []func(error) (string, bool)
+)
+
+// RegisterMapping registers a mapping from errors matching target, as
+// reported by errors.Is, to an error with the code and message. An empty
+// message uses the default message of the code. Mappings registered later
+// take precedence.
+func RegisterMapping(target error, code Code, msg string) {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+
+	mappings = append(mappings, mapping{target: target, code: code, msg: msg})
+}
+
+// RegisterDuplicateKeyMatcher registers a matcher detecting the
+// uniqueness violations of a database driver, such as the unique_violation
+// of pq or the ER_DUP_ENTRY of mysql. MapError and ResponseError convert the
+// errors it matches into a CodeConflict error, with the conflicting field,
+// when known, as "field" in the info. Matchers registered later take
+// precedence, and matchers are consulted before the mappings.
+//
+//	errs.RegisterDuplicateKeyMatcher(func(err error) (string, bool) {
+//		var pqErr *pq.Error
+//		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+//			return pqErr.Column, true
+//		}
+//		return "", false
+//	})
+func RegisterDuplicateKeyMatcher(match func(err error) (field string, ok bool)) {
+	duplicateKeyMatchersMu.Lock()
+	defer duplicateKeyMatchersMu.Unlock()
+
+	duplicateKeyMatchers = append(duplicateKeyMatchers, match)
+}
+
+// MapError converts err into an *Error. Errors that already wrap an *Error
+// are returned as is, well-known errors such as sql.ErrNoRows are converted
+// with the built-in and registered mappings, and any other error becomes a
+// CodeInternalServerError. The original error is kept as the cause. It
+// returns nil if err is nil.
+func MapError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := AsError(err); ok {
+		return e
+	}
+
+	if e, ok := lookupMapping(err); ok {
+		return e
+	}
+
+	return New(CodeInternalServerError, "", WithCause(err))
+}
+
+// lookupMapping converts err with the most recent matching duplicate key
+// matcher or mapping.
+func lookupMapping(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	if e, ok := lookupDuplicateKey(err); ok {
+		return e, true
+	}
+
+	mappingsMu.RLock()
+	ms := mappings
+	mappingsMu.RUnlock()
+
+	for i := len(ms) - 1; i >= 0; i-- {
+		if m := ms[i]; errors.Is(err, m.target) {
+			return New(m.code, m.msg, WithCause(err)), true
+		}
+	}
+
+	return nil, false
+}
+
+// lookupDuplicateKey converts err into a CodeConflict error with the most
+// recent matching duplicate key matcher.
+func lookupDuplicateKey(err error) (*Error, bool) {
+	duplicateKeyMatchersMu.RLock()
+	matchers := duplicateKeyMatchers
+	duplicateKeyMatchersMu.RUnlock()
+
+	for i := len(matchers) - 1; i >= 0; i-- {
+		field, ok := matchers[i](err)
+		if !ok {
+			continue
+		}
+
+		var info map[string]interface{}
+		if field != "" {
+			info = map[string]interface{}{"field": field}
+		}
+
+		return New(CodeConflict, "", WithCause(err), WithInfo(info)), true
+	}
+
+	return nil, false
+}
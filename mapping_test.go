@@ -0,0 +1,105 @@
+package errs_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+var errOutOfStock = errors.New("out of stock")
+
+func init() {
+	errs.RegisterMapping(errOutOfStock, errs.CodeConflict, "Item is out of stock")
+}
+
+func TestMapErrorNoRows(t *testing.T) {
+	err := fmt.Errorf("find user: %w", sql.ErrNoRows)
+
+	e := errs.MapError(err)
+	assert.Equal(t, errs.CodeNotFound, e.Code)
+	assert.Equal(t, "Not Found", e.Message)
+	assert.True(t, errors.Is(e, sql.ErrNoRows))
+}
+
+func TestMapErrorRegisteredMapping(t *testing.T) {
+	e := errs.MapError(fmt.Errorf("reserve item: %w", errOutOfStock))
+	assert.Equal(t, errs.CodeConflict, e.Code)
+	assert.Equal(t, "Item is out of stock", e.Message)
+}
+
+func TestMapErrorFallback(t *testing.T) {
+	err := errors.New("boom")
+
+	e := errs.MapError(err)
+	assert.Equal(t, errs.CodeInternalServerError, e.Code)
+	assert.Equal(t, err, errors.Unwrap(e))
+
+	assert.Same(t, errs.NotFound, errs.MapError(fmt.Errorf("wrapped: %w", errs.NotFound)))
+	assert.Nil(t, errs.MapError(nil))
+}
+
+func TestResponseErrorWithMappedError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, fmt.Errorf("find user: %w", sql.ErrNoRows))
+	})
+	router.POST("/cart", func(c *gin.Context) {
+		errs.ResponseError(c, errOutOfStock)
+	})
+
+	w := performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"NOT_FOUND"`)
+
+	w = performRequest(router, http.MethodPost, "/cart", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"Item is out of stock"`)
+}
+
+type duplicateKeyError struct {
+	column string
+}
+
+func (e *duplicateKeyError) Error() string { return "duplicate key value violates unique constraint" }
+
+func TestRegisterDuplicateKeyMatcher(t *testing.T) {
+	errs.RegisterDuplicateKeyMatcher(func(err error) (string, bool) {
+		var dup *duplicateKeyError
+		if errors.As(err, &dup) {
+			return dup.column, true
+		}
+		return "", false
+	})
+	t.Cleanup(errs.ResetDuplicateKeyMatchers)
+
+	cause := &duplicateKeyError{column: "email"}
+	e := errs.MapError(fmt.Errorf("insert user: %w", cause))
+	assert.Equal(t, errs.CodeConflict, e.Code)
+	assert.Equal(t, "Conflict", e.Message)
+	assert.Equal(t, map[string]interface{}{"field": "email"}, e.Info)
+	assert.ErrorIs(t, e, cause)
+
+	e = errs.MapError(&duplicateKeyError{})
+	assert.Equal(t, errs.CodeConflict, e.Code)
+	assert.Nil(t, e.Info)
+
+	assert.Equal(t, errs.CodeInternalServerError, errs.MapError(errors.New("boom")).Code)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/users", func(c *gin.Context) {
+		errs.ResponseError(c, cause)
+	})
+
+	w := performRequest(router, http.MethodPost, "/users", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"info":{"field":"email"}`)
+}
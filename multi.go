@@ -0,0 +1,113 @@
+package errs
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Multi aggregates several errors, e.g. the failures of a batch endpoint,
+// into a single error response.
+type Multi struct {
+	// Errors are the aggregated errors.
+	Errors []*Error
+}
+
+// Join returns a Multi aggregating the non-nil errors, or nil if there are
+// none. The result is a typed nil *Multi in that case, so check it before
+// returning it as an error:
+//
+//	if m := errs.Join(itemErrors...); m != nil {
+//		return m
+//	}
+//	return nil
+func Join(es ...*Error) *Multi {
+	m := &Multi{}
+	for _, e := range es {
+		if e != nil {
+			m.Errors = append(m.Errors, e)
+		}
+	}
+
+	if len(m.Errors) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+// Error returns the string representations of the errors separated by "; ".
+func (m *Multi) Error() string {
+	s := make([]string, len(m.Errors))
+	for i, e := range m.Errors {
+		s[i] = e.Error()
+	}
+
+	return strings.Join(s, "; ")
+}
+
+// Unwrap returns the aggregated errors.
+func (m *Multi) Unwrap() []error {
+	es := make([]error, len(m.Errors))
+	for i, e := range m.Errors {
+		es[i] = e
+	}
+
+	return es
+}
+
+// HTTPStatusCode returns the highest HTTP status code of the errors, so a
+// server error outranks a client error. An empty Multi is a server error.
+func (m *Multi) HTTPStatusCode() int {
+	return m.primary().HTTPStatusCode()
+}
+
+// Aggregate returns a copy of the error with the highest HTTP status code
+// that lists all the errors under info.errors.
+func (m *Multi) Aggregate() *Error {
+	return m.primary().Clone().AppendInfo("errors", m.Errors)
+}
+
+// MarshalJSON implements json.Marshaler by marshaling the aggregate error.
+func (m *Multi) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Aggregate())
+}
+
+// joined returns the errors found in a tree of errors joined with
+// errors.Join or any error with an Unwrap() []error method, in depth-first
+// order. The causes of the errors found are not walked.
+func joined(err error) []*Error {
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return []*Error{err}
+	case interface{ Unwrap() []error }:
+		var es []*Error
+		for _, err := range err.Unwrap() {
+			es = append(es, joined(err)...)
+		}
+
+		return es
+	case interface{ Unwrap() error }:
+		return joined(err.Unwrap())
+	}
+
+	return nil
+}
+
+// primary returns the first error with the highest HTTP status code, or
+// InternalServerError if there are no errors.
+func (m *Multi) primary() *Error {
+	if len(m.Errors) == 0 {
+		return InternalServerError
+	}
+
+	primary := m.Errors[0]
+	for _, e := range m.Errors[1:] {
+		if e.HTTPStatusCode() > primary.HTTPStatusCode() {
+			primary = e
+		}
+	}
+
+	return primary
+}
@@ -0,0 +1,123 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestJoin(t *testing.T) {
+	notFound := errs.New(errs.CodeNotFound, "Item 1 not found")
+	conflict := errs.New(errs.CodeConflict, "Item 2 already exists")
+	unavailable := errs.New(errs.CodeServiceUnavailable, "Inventory unavailable")
+
+	m := errs.Join(notFound, nil, conflict)
+	assert.Len(t, m.Errors, 2)
+	assert.Equal(t, http.StatusConflict, m.HTTPStatusCode())
+	assert.Equal(t, "[NOT_FOUND] Item 1 not found; [CONFLICT] Item 2 already exists", m.Error())
+
+	m = errs.Join(notFound, unavailable, conflict)
+	assert.Equal(t, http.StatusServiceUnavailable, m.HTTPStatusCode())
+	assert.Equal(t, errs.CodeServiceUnavailable, m.Aggregate().Code)
+	assert.Nil(t, unavailable.Info)
+
+	assert.Nil(t, errs.Join())
+	assert.Nil(t, errs.Join(nil, nil))
+}
+
+func TestMultiUnwrap(t *testing.T) {
+	var err error = errs.Join(errs.NotFound, errs.Conflict)
+	unwrapped := err.(interface{ Unwrap() []error }).Unwrap()
+	assert.Equal(t, []error{errs.NotFound, errs.Conflict}, unwrapped)
+}
+
+func TestMultiMarshalJSON(t *testing.T) {
+	m := errs.Join(
+		errs.New(errs.CodeNotFound, "Item 1 not found"),
+		errs.New(errs.CodeConflict, "Item 2 already exists"),
+	)
+
+	body, err := json.Marshal(m)
+	assert.NoError(t, err)
+
+	var got struct {
+		Code    errs.Code `json:"code"`
+		Message string    `json:"message"`
+		Info    struct {
+			Errors []struct {
+				Code    errs.Code `json:"code"`
+				Message string    `json:"message"`
+			} `json:"errors"`
+		} `json:"info"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, errs.CodeConflict, got.Code)
+	assert.Equal(t, "Item 2 already exists", got.Message)
+	if assert.Len(t, got.Info.Errors, 2) {
+		assert.Equal(t, errs.CodeNotFound, got.Info.Errors[0].Code)
+		assert.Equal(t, "Item 1 not found", got.Info.Errors[0].Message)
+		assert.Equal(t, errs.CodeConflict, got.Info.Errors[1].Code)
+	}
+}
+
+func TestResponseErrorWithMulti(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.POST("/batch", func(c *gin.Context) {
+		m := errs.Join(errs.NotFound, errs.New(errs.CodeUnprocessableEntity, "Item 2 is invalid"))
+		errs.ResponseError(c, m)
+	})
+
+	w := performRequest(router, http.MethodPost, "/batch", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"errors":[`)
+}
+
+func TestResponseErrorWithWrappedMulti(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.POST("/batch", func(c *gin.Context) {
+		errs.ResponseError(c, fmt.Errorf("batch: %w", errs.Join(errs.NotFound)))
+	})
+
+	w := performRequest(router, http.MethodPost, "/batch", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestResponseErrorWithErrorWrappingMulti(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.POST("/batch", func(c *gin.Context) {
+		errs.ResponseError(c, errs.Wrap(errs.Join(errs.NotFound, errs.Conflict), errs.CodeBadRequest, "Batch rejected"))
+	})
+
+	w := performRequest(router, http.MethodPost, "/batch", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotContains(t, w.Body.String(), `"errors"`)
+}
+
+func TestEmptyMulti(t *testing.T) {
+	m := &errs.Multi{}
+	assert.Equal(t, http.StatusInternalServerError, m.HTTPStatusCode())
+	assert.Equal(t, errs.CodeInternalServerError, m.Aggregate().Code)
+
+	_, err := json.Marshal(m)
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/batch", func(c *gin.Context) {
+		errs.ResponseError(c, m)
+	})
+
+	w := performRequest(router, http.MethodPost, "/batch", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
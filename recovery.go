@@ -0,0 +1,45 @@
+package errs
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery is a gin middleware that recovers panics and writes the error
+// with ResponseError. A panic with an error resolved by Resolve, such as an
+// *Error or an error matching a mapping, is written as that error, e.g. a
+// panic with sql.ErrNoRows becomes a 404. Other panics are written as a 500 like
+// Recover does. The panic and its stack are logged with the configured
+// logger.
+//
+//	router := gin.New()
+//	router.Use(errs.Recovery())
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			ctx := requestContext(c)
+			logPanic(ctx, rec)
+
+			if err, ok := rec.(error); ok {
+				if e, ok := Resolve(err); ok {
+					ResponseError(c, e)
+					return
+				}
+			}
+
+			ResponseError(c, panicError(ctx, rec))
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package errs_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestRecovery(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(errs.Recovery())
+	router.GET("/mapped", func(c *gin.Context) {
+		panic(fmt.Errorf("load user: %w", sql.ErrNoRows))
+	})
+	router.GET("/error", func(c *gin.Context) {
+		panic(errs.New(errs.CodeConflict, "Order already exists"))
+	})
+	router.GET("/unknown", func(c *gin.Context) {
+		panic(fmt.Errorf("boom"))
+	})
+	router.GET("/value", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/mapped", http.StatusNotFound, `"code":"NOT_FOUND"`},
+		{"/error", http.StatusConflict, `"code":"CONFLICT"`},
+		{"/unknown", http.StatusInternalServerError, `"code":"INTERNAL_SERVER_ERROR"`},
+		{"/value", http.StatusInternalServerError, `"code":"INTERNAL_SERVER_ERROR"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := performRequest(router, http.MethodGet, tt.path, nil)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.code)
+			assert.NotContains(t, w.Body.String(), "boom")
+		})
+	}
+
+	var panics int
+	for _, entry := range l.Entries() {
+		if entry.Message == "errs: recovered panic" {
+			panics++
+			assert.NotEmpty(t, entry.Fields["stack"])
+		}
+	}
+	assert.Equal(t, len(tests), panics)
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(errs.Recovery())
+	router.GET("/abort", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		performRequest(router, http.MethodGet, "/abort", nil)
+	})
+}
+
+func TestRecoveryResolvesOnce(t *testing.T) {
+	t.Cleanup(errs.ResetErrorHooks)
+
+	var calls int
+	errs.OnError(func(e *errs.Error) {
+		if e.Code == errs.CodeNotFound {
+			calls++
+		}
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(errs.Recovery())
+	router.GET("/mapped", func(c *gin.Context) {
+		panic(sql.ErrNoRows)
+	})
+
+	w := performRequest(router, http.MethodGet, "/mapped", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,107 @@
+package errs
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the values of redacted keys.
+const redacted = "[REDACTED]"
+
+// redactedKeys holds the lower case keys registered with RegisterRedactedKeys.
+var (
+	redactedKeysMu sync.RWMutex
+	redactedKeys   = make(map[string]struct{})
+)
+
+// RegisterRedactedKeys registers info keys whose values are replaced with
+// "[REDACTED]" when an error is serialized. Keys are matched case
+// insensitively, including keys of nested maps.
+func RegisterRedactedKeys(keys ...string) {
+	redactedKeysMu.Lock()
+	defer redactedKeysMu.Unlock()
+
+	for _, key := range keys {
+		redactedKeys[strings.ToLower(key)] = struct{}{}
+	}
+}
+
+// isRedacted reports whether the key is registered as redacted.
+func isRedacted(key string) bool {
+	redactedKeysMu.RLock()
+	defer redactedKeysMu.RUnlock()
+
+	_, ok := redactedKeys[strings.ToLower(key)]
+	return ok
+}
+
+// hasRedactedKeys reports whether any key is registered as redacted.
+func hasRedactedKeys() bool {
+	redactedKeysMu.RLock()
+	defer redactedKeysMu.RUnlock()
+
+	return len(redactedKeys) > 0
+}
+
+// redactInfo returns a copy of info with the values of redacted keys
+// replaced. It returns info itself if no key is registered as redacted.
+func redactInfo(info map[string]interface{}) map[string]interface{} {
+	if info == nil || !hasRedactedKeys() {
+		return info
+	}
+
+	result := make(map[string]interface{}, len(info))
+	for k, v := range info {
+		result[k] = redactValue(k, v)
+	}
+
+	return result
+}
+
+// redactedInfo returns the info of the error with redaction applied. The
+// validation messages are kept, so a field registered as redacted still
+// tells the client why it failed; only its rejected value is redacted.
+func (e *Error) redactedInfo() map[string]interface{} {
+	info := e.info()
+	if info == nil || !hasRedactedKeys() {
+		return info
+	}
+
+	result := make(map[string]interface{}, len(info))
+	for k, v := range info {
+		if msg, ok := e.validationMessages[k]; ok && reflect.DeepEqual(v, msg) {
+			result[k] = v
+			continue
+		}
+
+		result[k] = redactValue(k, v)
+	}
+
+	return result
+}
+
+// redactValue returns the value of the key with redaction applied.
+func redactValue(key string, v interface{}) interface{} {
+	if isRedacted(key) {
+		return redacted
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return redactInfo(v)
+	case map[string]string:
+		result := make(map[string]string, len(v))
+		for k, s := range v {
+			if isRedacted(k) {
+				s = redacted
+			}
+
+			result[k] = s
+		}
+
+		return result
+	}
+
+	return v
+}
@@ -0,0 +1,63 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestRegisterRedactedKeys(t *testing.T) {
+	errs.RegisterRedactedKeys("password", "Token")
+	t.Cleanup(errs.ResetRedactedKeys)
+
+	info := map[string]interface{}{
+		"username": "alice",
+		"Password": "hunter2",
+		"token":    "abc123",
+		"account": map[string]interface{}{
+			"id":       42,
+			"PASSWORD": "hunter2",
+		},
+		"headers": map[string]string{
+			"Accept": "application/json",
+			"token":  "abc123",
+		},
+	}
+	err := errs.New(errs.CodeUnauthorized, "Invalid credentials", errs.WithInfo(info))
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+
+	var got struct {
+		Info map[string]interface{} `json:"info"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, map[string]interface{}{
+		"username": "alice",
+		"Password": "[REDACTED]",
+		"token":    "[REDACTED]",
+		"account": map[string]interface{}{
+			"id":       float64(42),
+			"PASSWORD": "[REDACTED]",
+		},
+		"headers": map[string]interface{}{
+			"Accept": "application/json",
+			"token":  "[REDACTED]",
+		},
+	}, got.Info)
+
+	assert.Equal(t, "hunter2", err.Info["Password"], "the error itself is not modified")
+}
+
+func TestMarshalJSONWithoutRedactedKeys(t *testing.T) {
+	err := errs.New(errs.CodeBadRequest, "Bad request", errs.WithInfo(map[string]interface{}{
+		"password": "hunter2",
+	}))
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"password":"hunter2"`)
+	assert.Contains(t, string(body), `"code":"BAD_REQUEST"`)
+}
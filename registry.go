@@ -0,0 +1,229 @@
+package errs
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// CodeSpec describes an error code: its HTTP status code and the message
+// used by New when the message is empty.
+type CodeSpec struct {
+	// Code is the error code.
+	Code Code
+
+	// HTTPStatus is the HTTP status code of the errors with the code.
+	HTTPStatus int
+
+	// DefaultMessage is the message of errors created without one. The HTTP
+	// status text, or the code title, is used when it is empty.
+	DefaultMessage string
+
+	// Retryable reports whether the code represents a transient condition,
+	// see Error.IsRetryable.
+	Retryable bool
+}
+
+// builtinStatuses maps the built-in error codes to their HTTP status codes.
+var builtinStatuses = map[Code]int{
+	CodeBadRequest:                 http.StatusBadRequest,
+	CodeUnauthorized:               http.StatusUnauthorized,
+	CodePaymentRequired:            http.StatusPaymentRequired,
+	CodeForbidden:                  http.StatusForbidden,
+	CodeNotFound:                   http.StatusNotFound,
+	CodeConflict:                   http.StatusConflict,
+	CodeGone:                       http.StatusGone,
+	CodeUnprocessableEntity:        http.StatusUnprocessableEntity,
+	CodeValidationFailed:           http.StatusUnprocessableEntity,
+	CodeTooManyRequests:            http.StatusTooManyRequests,
+	CodeUnavailableForLegalReasons: http.StatusUnavailableForLegalReasons,
+	CodeClientClosedRequest:        StatusClientClosedRequest,
+	CodeInternalServerError:        http.StatusInternalServerError,
+	CodeNotImplemented:             http.StatusNotImplemented,
+	CodeServiceUnavailable:         http.StatusServiceUnavailable,
+	CodeTimeout:                    http.StatusGatewayTimeout,
+}
+
+// retryableCodes are the built-in codes of transient conditions.
+var retryableCodes = map[Code]bool{
+	CodeTooManyRequests:    true,
+	CodeServiceUnavailable: true,
+	CodeTimeout:            true,
+}
+
+// defaultMessages are the default messages of the built-in codes whose HTTP
+// status text does not fit.
+var defaultMessages = map[Code]string{
+	CodeValidationFailed: "Validation Failed",
+}
+
+// codeSpecs holds the specs of the built-in and registered error codes.
+var (
+	codeSpecsMu sync.RWMutex
+	codeSpecs   = builtinSpecs()
+)
+
+// builtinSpecs returns the specs of the built-in codes.
+func builtinSpecs() map[Code]CodeSpec {
+	specs := make(map[Code]CodeSpec, len(builtinStatuses))
+	for code, status := range builtinStatuses {
+		specs[code] = CodeSpec{
+			Code:           code,
+			HTTPStatus:     status,
+			DefaultMessage: defaultMessages[code],
+			Retryable:      retryableCodes[code],
+		}
+	}
+
+	return specs
+}
+
+// statusCodes maps the HTTP status codes to the built-in error codes. When
+// several codes share a status, such as CodeUnprocessableEntity and
+// CodeValidationFailed, the first in sorted order is used.
+var statusCodes = func() map[int]Code {
+	m := make(map[int]Code, len(builtinStatuses))
+	for code, status := range builtinStatuses {
+		if c, ok := m[status]; !ok || code < c {
+			m[status] = code
+		}
+	}
+
+	return m
+}()
+
+// CodeFromStatus returns the code for the HTTP status code. Built-in codes
+// take precedence over registered ones, and when several registered codes
+// share the status the first in sorted order is returned. It defaults to
+// CodeInternalServerError for unmapped statuses.
+func CodeFromStatus(status int) Code {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+
+	for _, code := range Codes() {
+		if s, _ := codeStatus(code); s == status {
+			return code
+		}
+	}
+
+	return CodeInternalServerError
+}
+
+// codeStatus returns the HTTP status code for the code and whether the code
+// is known.
+func codeStatus(code Code) (int, bool) {
+	spec, ok := codeSpec(code)
+	return spec.HTTPStatus, ok
+}
+
+// codeSpec returns the spec of the code and whether the code is known.
+func codeSpec(code Code) (CodeSpec, bool) {
+	codeSpecsMu.RLock()
+	defer codeSpecsMu.RUnlock()
+
+	spec, ok := codeSpecs[code]
+	return spec, ok
+}
+
+// RegisterCode registers a custom error code with its HTTP status code. It
+// returns an error if the code is empty, the status is not a valid HTTP
+// status code, or the code is already registered.
+func RegisterCode(code Code, status int) error {
+	return RegisterCodeSpecs(CodeSpec{Code: code, HTTPStatus: status})
+}
+
+// RegisterCodes registers several custom error codes with their HTTP status
+// codes. Either all codes are registered or, if any of them is invalid or
+// already registered, none is and the error describes the first conflict in
+// sorted code order.
+func RegisterCodes(m map[Code]int) error {
+	specs := make([]CodeSpec, 0, len(m))
+	for code, status := range m {
+		specs = append(specs, CodeSpec{Code: code, HTTPStatus: status})
+	}
+
+	return RegisterCodeSpecs(specs...)
+}
+
+// RegisterCodeSpecs registers custom error codes with their HTTP status codes
+// and default messages, like RegisterCodes.
+//
+//	errs.RegisterCodeSpecs(errs.CodeSpec{
+//		Code:           "QUOTA_EXCEEDED",
+//		HTTPStatus:     http.StatusTooManyRequests,
+//		DefaultMessage: "You have exceeded your quota",
+//	})
+func RegisterCodeSpecs(specs ...CodeSpec) error {
+	sorted := append([]CodeSpec(nil), specs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Code < sorted[j].Code })
+
+	codeSpecsMu.Lock()
+	defer codeSpecsMu.Unlock()
+
+	for i, spec := range sorted {
+		if err := checkCode(spec.Code, spec.HTTPStatus); err != nil {
+			return err
+		}
+
+		if i > 0 && sorted[i-1].Code == spec.Code {
+			return fmt.Errorf("errs: code %q is already registered", spec.Code)
+		}
+	}
+
+	for _, spec := range sorted {
+		codeSpecs[spec.Code] = spec
+	}
+
+	return nil
+}
+
+// checkCode returns an error if the code cannot be registered with the
+// status. The caller must hold codeSpecsMu.
+func checkCode(code Code, status int) error {
+	if code == "" {
+		return fmt.Errorf("errs: empty code")
+	}
+
+	if status < 100 || status > 599 {
+		return fmt.Errorf("errs: invalid status %d for code %q", status, code)
+	}
+
+	if _, ok := codeSpecs[code]; ok {
+		return fmt.Errorf("errs: code %q is already registered", code)
+	}
+
+	return nil
+}
+
+// Codes returns the built-in and registered codes sorted in ascending
+// order. The returned slice is a copy and may be modified by the caller.
+func Codes() []Code {
+	codeSpecsMu.RLock()
+	codes := make([]Code, 0, len(codeSpecs))
+	for code := range codeSpecs {
+		codes = append(codes, code)
+	}
+	codeSpecsMu.RUnlock()
+
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// Valid reports whether the code is a built-in or registered code.
+func (c Code) Valid() bool {
+	_, ok := codeStatus(c)
+	return ok
+}
+
+// ParseCode returns the code for s, or an error if s is not a built-in or
+// registered code.
+func ParseCode(s string) (Code, error) {
+	code := Code(s)
+	if !code.Valid() {
+		return "", fmt.Errorf("errs: unknown code %q", s)
+	}
+
+	return code, nil
+}
@@ -0,0 +1,232 @@
+package errs_test
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestRegisterCode(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	code := errs.Code("CARD_DECLINED")
+	assert.NoError(t, errs.RegisterCode(code, http.StatusPaymentRequired))
+	assert.Equal(t, http.StatusPaymentRequired, errs.New(code, "").HTTPStatusCode())
+
+	assert.Error(t, errs.RegisterCode(code, http.StatusBadRequest))
+	assert.Error(t, errs.RegisterCode(errs.CodeNotFound, http.StatusNotFound))
+	assert.Error(t, errs.RegisterCode("", http.StatusBadRequest))
+	assert.Error(t, errs.RegisterCode("INVALID_STATUS", 42))
+}
+
+func TestCodeValid(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	assert.True(t, errs.CodeNotFound.Valid())
+	assert.False(t, errs.Code("NOT_REGISTERED").Valid())
+
+	code := errs.Code("QUOTA_EXCEEDED")
+	assert.False(t, code.Valid())
+	assert.NoError(t, errs.RegisterCode(code, http.StatusTooManyRequests))
+	assert.True(t, code.Valid())
+}
+
+func TestParseCode(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	code, err := errs.ParseCode("NOT_FOUND")
+	assert.NoError(t, err)
+	assert.Equal(t, errs.CodeNotFound, code)
+
+	code, err = errs.ParseCode("not_found")
+	assert.Error(t, err)
+	assert.Empty(t, code)
+
+	assert.NoError(t, errs.RegisterCode("ACCOUNT_LOCKED", http.StatusForbidden))
+	code, err = errs.ParseCode("ACCOUNT_LOCKED")
+	assert.NoError(t, err)
+	assert.Equal(t, errs.Code("ACCOUNT_LOCKED"), code)
+}
+
+func TestCodes(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	assert.NoError(t, errs.RegisterCode("BILLING_SUSPENDED", http.StatusPaymentRequired))
+
+	codes := errs.Codes()
+	assert.Subset(t, codes, []errs.Code{
+		errs.CodeBadRequest,
+		errs.CodeNotFound,
+		errs.CodeInternalServerError,
+		errs.CodeTimeout,
+		"BILLING_SUSPENDED",
+	})
+	assert.True(t, sort.SliceIsSorted(codes, func(i, j int) bool { return codes[i] < codes[j] }))
+
+	codes[0] = "MUTATED"
+	assert.NotContains(t, errs.Codes(), errs.Code("MUTATED"))
+}
+
+func TestCodeFromStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   errs.Code
+	}{
+		{http.StatusBadRequest, errs.CodeBadRequest},
+		{http.StatusUnauthorized, errs.CodeUnauthorized},
+		{http.StatusPaymentRequired, errs.CodePaymentRequired},
+		{http.StatusForbidden, errs.CodeForbidden},
+		{http.StatusNotFound, errs.CodeNotFound},
+		{http.StatusConflict, errs.CodeConflict},
+		{http.StatusGone, errs.CodeGone},
+		{http.StatusUnprocessableEntity, errs.CodeUnprocessableEntity},
+		{http.StatusTooManyRequests, errs.CodeTooManyRequests},
+		{http.StatusUnavailableForLegalReasons, errs.CodeUnavailableForLegalReasons},
+		{errs.StatusClientClosedRequest, errs.CodeClientClosedRequest},
+		{http.StatusInternalServerError, errs.CodeInternalServerError},
+		{http.StatusNotImplemented, errs.CodeNotImplemented},
+		{http.StatusServiceUnavailable, errs.CodeServiceUnavailable},
+		{http.StatusGatewayTimeout, errs.CodeTimeout},
+		{http.StatusTeapot, errs.CodeInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.want, errs.CodeFromStatus(tt.status))
+		})
+	}
+}
+
+func TestCodeFromStatusWithRegisteredCode(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	assert.NoError(t, errs.RegisterCode("UPSTREAM_FAILED", http.StatusBadGateway))
+	assert.Equal(t, errs.Code("UPSTREAM_FAILED"), errs.CodeFromStatus(http.StatusBadGateway))
+
+	assert.NoError(t, errs.RegisterCode("RESOURCE_LOCKED", http.StatusNotFound))
+	assert.Equal(t, errs.CodeNotFound, errs.CodeFromStatus(http.StatusNotFound))
+}
+
+func TestRegisterCodes(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	assert.NoError(t, errs.RegisterCodes(map[errs.Code]int{
+		"INSUFFICIENT_FUNDS": http.StatusPaymentRequired,
+		"CARD_EXPIRED":       http.StatusPaymentRequired,
+	}))
+	assert.True(t, errs.Code("INSUFFICIENT_FUNDS").Valid())
+	assert.True(t, errs.Code("CARD_EXPIRED").Valid())
+}
+
+func TestRegisterCodesConflict(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	err := errs.RegisterCodes(map[errs.Code]int{
+		"ORDER_CANCELLED": http.StatusConflict,
+		"NOT_FOUND":       http.StatusNotFound,
+		"ORDER_SHIPPED":   http.StatusConflict,
+	})
+	assert.EqualError(t, err, `errs: code "NOT_FOUND" is already registered`)
+	assert.False(t, errs.Code("ORDER_CANCELLED").Valid())
+	assert.False(t, errs.Code("ORDER_SHIPPED").Valid())
+
+	err = errs.RegisterCodes(map[errs.Code]int{
+		"ORDER_CANCELLED": http.StatusConflict,
+		"ORDER_LOST":      1000,
+	})
+	assert.Error(t, err)
+	assert.False(t, errs.Code("ORDER_CANCELLED").Valid())
+}
+
+func TestRegisterCodeSpecs(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	assert.NoError(t, errs.RegisterCodeSpecs(errs.CodeSpec{
+		Code:           "QUOTA_EXCEEDED",
+		HTTPStatus:     http.StatusTooManyRequests,
+		DefaultMessage: "You have exceeded your quota",
+	}, errs.CodeSpec{
+		Code:       "BAD_GATEWAY",
+		HTTPStatus: http.StatusBadGateway,
+	}))
+
+	err := errs.New("QUOTA_EXCEEDED", "")
+	assert.Equal(t, http.StatusTooManyRequests, err.HTTPStatusCode())
+	assert.Equal(t, "You have exceeded your quota", err.Message)
+
+	err = errs.New("QUOTA_EXCEEDED", "Daily quota reached")
+	assert.Equal(t, "Daily quota reached", err.Message)
+
+	err = errs.New("BAD_GATEWAY", "")
+	assert.Equal(t, http.StatusBadGateway, err.HTTPStatusCode())
+	assert.Equal(t, "Bad Gateway", err.Message)
+}
+
+func TestRegisterCodeSpecsConflict(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	err := errs.RegisterCodeSpecs(
+		errs.CodeSpec{Code: "ORDER_LOST", HTTPStatus: http.StatusGone},
+		errs.CodeSpec{Code: "ORDER_LOST", HTTPStatus: http.StatusNotFound},
+	)
+	assert.EqualError(t, err, `errs: code "ORDER_LOST" is already registered`)
+	assert.False(t, errs.Code("ORDER_LOST").Valid())
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Cleanup(errs.ResetCodes)
+
+	assert.NoError(t, errs.RegisterCodeSpecs(
+		errs.CodeSpec{Code: "UPSTREAM_BUSY", HTTPStatus: http.StatusServiceUnavailable, Retryable: true},
+		errs.CodeSpec{Code: "QUOTA_EXHAUSTED", HTTPStatus: http.StatusTooManyRequests},
+	))
+
+	tests := []struct {
+		code errs.Code
+		want bool
+	}{
+		{errs.CodeTooManyRequests, true},
+		{errs.CodeServiceUnavailable, true},
+		{errs.CodeTimeout, true},
+		{"UPSTREAM_BUSY", true},
+		{errs.CodeBadRequest, false},
+		{errs.CodeNotFound, false},
+		{errs.CodeInternalServerError, false},
+		{"QUOTA_EXHAUSTED", false},
+		{"UNKNOWN", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, errs.New(tt.code, "").IsRetryable())
+		})
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		msg     []string
+		code    errs.Code
+		message string
+	}{
+		{"not found", http.StatusNotFound, nil, errs.CodeNotFound, "Not Found"},
+		{"with message", http.StatusConflict, []string{"Order already exists"}, errs.CodeConflict, "Order already exists"},
+		{"empty message", http.StatusGatewayTimeout, []string{""}, errs.CodeTimeout, "Gateway Timeout"},
+		{"client closed", errs.StatusClientClosedRequest, nil, errs.CodeClientClosedRequest, "Client Closed Request"},
+		{"unmapped", http.StatusTeapot, nil, errs.CodeInternalServerError, "Internal Server Error"},
+		{"invalid", 1000, []string{"upstream failed"}, errs.CodeInternalServerError, "upstream failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errs.FromStatus(tt.status, tt.msg...)
+			assert.Equal(t, tt.code, err.Code)
+			assert.Equal(t, tt.message, err.Message)
+		})
+	}
+}
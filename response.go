@@ -0,0 +1,228 @@
+package errs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ResponseError returns an error response. The body is JSON unless the
+// client asks for XML or plain text; plain text is the "[CODE] message" line.
+// The public message is used when set. Errors matching a mapping, such as
+// sql.ErrNoRows or context deadline and cancellation errors, are converted
+// like MapError does instead of returning a 500. Errors joined with
+// errors.Join are aggregated like Join when they contain several *Error
+// values, see Resolve. The gin context is aborted, so the remaining handlers
+// of the chain are not called. When the response is already written, the
+// error is logged instead of written a second time. The hooks registered
+// with OnResponse run before the response is written.
+func ResponseError(c *gin.Context, err error) {
+	respond(c, err, func(status int, e *Error, text string) {
+		if e == nil {
+			render(c, status, fallback(text), text)
+			return
+		}
+
+		render(c, status, exposed(e), e.publicError())
+	})
+}
+
+// respond resolves err like ResponseError does and calls write with the
+// status and the resolved error, or a nil error and the fallback message for
+// unknown errors. The request ID and error headers are set and the response
+// hooks run before write is called.
+func respond(c *gin.Context, err error, write func(status int, e *Error, text string)) {
+	defer c.Abort()
+
+	if c.Writer.Written() {
+		log(requestContext(c), SeverityWarn, "errs: response already written", map[string]interface{}{
+			"error":  err,
+			"status": c.Writer.Status(),
+		})
+		return
+	}
+
+	requestID := requestID(c)
+	if requestID != "" {
+		c.Header("X-Request-ID", requestID)
+	}
+
+	e, ok := responseErr(err, requestID)
+	if ok {
+		for k, v := range e.headers {
+			c.Header(k, v)
+		}
+
+		if header := currentConfig().codeHeader; header != "" {
+			c.Header(header, e.Code.String())
+		}
+
+		runResponseHooks(c, e)
+		write(e.HTTPStatusCode(), e, e.publicError())
+		return
+	}
+
+	text := fallbackMessage(requestID)
+	runResponseHooks(c, &Error{
+		Code:      CodeInternalServerError,
+		Message:   text,
+		Timestamp: now(),
+		Severity:  SeverityError,
+		cause:     err,
+	})
+	write(http.StatusInternalServerError, nil, text)
+}
+
+// requestContext returns the context of the gin request, or the background
+// context if there is no request.
+func requestContext(c *gin.Context) context.Context {
+	if c.Request == nil {
+		return context.Background()
+	}
+
+	return c.Request.Context()
+}
+
+// fallbackMessage returns the message written for unknown errors, set with
+// SetFallbackMessage, with the request ID placeholder replaced.
+func fallbackMessage(requestID string) string {
+	return strings.ReplaceAll(currentConfig().fallbackMessage, "{requestID}", requestID)
+}
+
+// fallback is the body written for unknown errors. It is a JSON string, and
+// an <error> element in XML.
+type fallback string
+
+// Resolve returns the *Error written in responses for err, for use by
+// framework adapters. A *Multi or several *Error values joined with
+// errors.Join are aggregated, and errors matching a mapping are converted
+// like MapError does. It reports false for unknown errors, which are written
+// as a 500.
+func Resolve(err error) (*Error, bool) {
+	e, ok := aggregate(err)
+	if !ok {
+		e, ok = lookupMapping(err)
+	}
+
+	return e, ok
+}
+
+// responseErr resolves err with Resolve. The request ID, when set, is added
+// to a copy of the error.
+func responseErr(err error, requestID string) (*Error, bool) {
+	e, ok := Resolve(err)
+	if ok && requestID != "" {
+		e = e.Clone().AppendInfo("requestID", requestID)
+	}
+
+	return e, ok
+}
+
+// exposed returns the error written in response bodies: e, or a copy
+// without the info and data when disabled with SetExposeInfo, or with the
+// causes in the info when enabled with SetDevMode.
+func exposed(e *Error) *Error {
+	conf := currentConfig()
+	if !conf.exposeInfo {
+		e = e.Clone()
+		e.Info, e.Data, e.lazyInfo = nil, nil, nil
+		return e
+	}
+
+	if conf.devMode {
+		if list := causes(e); len(list) > 0 {
+			e = e.Clone().AppendInfo("causes", list)
+		}
+	}
+
+	return e
+}
+
+// causes lists the errors wrapped below e, with the code of *Error causes
+// and the message. The walk stops when an error repeats.
+func causes(e *Error) []map[string]interface{} {
+	var list []map[string]interface{}
+	walkChain(e, func(err error) bool {
+		entry := map[string]interface{}{"message": err.Error()}
+		if c, ok := err.(*Error); ok {
+			entry["code"] = c.Code
+			entry["message"] = c.message()
+		}
+
+		list = append(list, entry)
+		return true
+	})
+
+	return list
+}
+
+// aggregate walks the Unwrap chain of err down to the first *Error, *Multi or
+// joined error. A *Multi is aggregated, and so are the errors joined with
+// errors.Join when they contain several *Error values. The aggregate lists
+// all of them under info.errors, including the primary one, like Join does;
+// joined errors that are not *Error values are left out. The causes of an
+// *Error are not walked, so an *Error wrapping a *Multi is used as is.
+func aggregate(err error) (*Error, bool) {
+	switch v := err.(type) {
+	case nil:
+		return nil, false
+	case *Error:
+		return v, true
+	case *Multi:
+		return v.Aggregate(), true
+	case interface{ Unwrap() []error }:
+		es := joined(err)
+		switch len(es) {
+		case 0:
+			return nil, false
+		case 1:
+			return es[0], true
+		}
+
+		return Join(es...).Aggregate(), true
+	case interface{ Unwrap() error }:
+		return aggregate(v.Unwrap())
+	}
+
+	return nil, false
+}
+
+// render writes the body in the format negotiated with the client,
+// defaulting to JSON, which follows the envelope, content type and pretty
+// settings.
+// The text is written for plain text clients.
+func render(c *gin.Context, status int, body interface{}, text string) {
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2, binding.MIMEPlain) {
+	case binding.MIMEXML, binding.MIMEXML2:
+		c.XML(status, body)
+	case binding.MIMEPlain:
+		c.String(status, text)
+	default:
+		conf := currentConfig()
+		c.Header("Content-Type", conf.contentType)
+		if conf.pretty {
+			c.IndentedJSON(status, jsonBody(body))
+			return
+		}
+
+		c.JSON(status, jsonBody(body))
+	}
+}
+
+// requestID returns the request ID stored in the gin context, if any.
+func requestID(c *gin.Context) string {
+	key := currentConfig().requestIDKey
+	if key == "" {
+		return ""
+	}
+
+	if v, ok := c.Get(key); ok && v != nil {
+		return fmt.Sprint(v)
+	}
+
+	return ""
+}
@@ -0,0 +1,430 @@
+package errs_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestResponseErrorWithPlainText(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/plain", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "User not found"))
+	})
+
+	w := performRequestWithHeader(router, http.MethodGet, "/plain", "Accept", "text/plain")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "[NOT_FOUND] User not found", w.Body.String())
+}
+
+func TestResponseErrorWithXML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/xml", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "User not found", errs.WithInfo(map[string]interface{}{
+			"userId": 42,
+		})))
+	})
+
+	w := performRequestWithHeader(router, http.MethodGet, "/xml", "Accept", "application/xml")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var got struct {
+		XMLName xml.Name `xml:"error"`
+		Code    string   `xml:"code"`
+		Message string   `xml:"message"`
+		Entries []struct {
+			Key   string `xml:"key,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"info>entry"`
+	}
+	assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "NOT_FOUND", got.Code)
+	assert.Equal(t, "User not found", got.Message)
+	if assert.Len(t, got.Entries, 1) {
+		assert.Equal(t, "userId", got.Entries[0].Key)
+		assert.Equal(t, "42", got.Entries[0].Value)
+	}
+
+	w = performRequestWithHeader(router, http.MethodGet, "/xml", "Accept", "application/json")
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"NOT_FOUND"`)
+}
+
+func TestResponseErrorWithXMLAggregate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/batch", func(c *gin.Context) {
+		errs.ResponseError(c, errs.Join(
+			errs.New(errs.CodeNotFound, "internal db detail", errs.WithPublicMessage("Item not found")),
+			errs.New(errs.CodeConflict, "secret conflict", errs.WithPublicMessage("Item exists")),
+		))
+	})
+	router.GET("/unknown", func(c *gin.Context) {
+		errs.ResponseError(c, errors.New("boom"))
+	})
+
+	w := performRequestWithHeader(router, http.MethodGet, "/batch", "Accept", "application/xml")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.NotContains(t, w.Body.String(), "internal db detail")
+	assert.NotContains(t, w.Body.String(), "secret conflict")
+
+	var got struct {
+		Code   string `xml:"code"`
+		Errors []struct {
+			Code    string `xml:"code"`
+			Message string `xml:"message"`
+		} `xml:"info>entry>error"`
+	}
+	assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "CONFLICT", got.Code)
+	if assert.Len(t, got.Errors, 2) {
+		assert.Equal(t, "Item not found", got.Errors[0].Message)
+		assert.Equal(t, "Item exists", got.Errors[1].Message)
+	}
+
+	w = performRequestWithHeader(router, http.MethodGet, "/unknown", "Accept", "application/xml")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "<error><code>INTERNAL_SERVER_ERROR</code><message>Internal Server Error</message></error>")
+}
+
+func TestResponseErrorPrefersJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/json", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "User not found"))
+	})
+
+	for _, accept := range []string{"", "*/*", "application/json, text/plain", "text/html"} {
+		w := performRequestWithHeader(router, http.MethodGet, "/json", "Accept", accept)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), accept)
+	}
+}
+
+func TestResponseErrorWithPublicMessage(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	err := errs.New(errs.CodeInternalServerError, "insert order: duplicate key orders_pkey",
+		errs.WithPublicMessage("Could not place the order"),
+		errs.WithLogErr(errors.New("pq: duplicate key")),
+	)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/public", func(c *gin.Context) {
+		errs.ResponseError(c, err)
+	})
+
+	w := performRequest(router, http.MethodGet, "/public", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"Could not place the order"`)
+	assert.NotContains(t, w.Body.String(), "orders_pkey")
+
+	w = performRequestWithHeader(router, http.MethodGet, "/public", "Accept", "text/plain")
+	assert.Equal(t, "[INTERNAL_SERVER_ERROR] Could not place the order", w.Body.String())
+
+	entry := lastEntry(l)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "insert order: duplicate key orders_pkey", entry.Message)
+	}
+}
+
+func TestResponseErrorWithRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/request-id", func(c *gin.Context) {
+		c.Set("requestID", "req-123")
+		errs.ResponseError(c, errs.NotFound)
+	})
+
+	w := performRequest(router, http.MethodGet, "/request-id", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "req-123", w.Header().Get("X-Request-ID"))
+	assert.Contains(t, w.Body.String(), `"info":{"requestID":"req-123"}`)
+	assert.Nil(t, errs.NotFound.Info)
+}
+
+func TestResponseErrorWithoutRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/request-id", func(c *gin.Context) {
+		errs.ResponseError(c, errs.NotFound)
+	})
+
+	w := performRequest(router, http.MethodGet, "/request-id", nil)
+	assert.Empty(t, w.Header().Get("X-Request-ID"))
+	assert.NotContains(t, w.Body.String(), "requestID")
+}
+
+func TestSetRequestIDKey(t *testing.T) {
+	errs.SetRequestIDKey("rid")
+	t.Cleanup(func() { errs.SetRequestIDKey("requestID") })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	router.GET("/request-id", func(c *gin.Context) {
+		c.Set("rid", "req-456")
+		errs.ResponseError(c, errors.New("boom"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/request-id", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "req-456", w.Header().Get("X-Request-ID"))
+}
+
+func TestResponseErrorWithContextErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+		code errs.Code
+	}{
+		{"deadline", fmt.Errorf("query users: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, errs.CodeTimeout},
+		{"canceled", fmt.Errorf("query users: %w", context.Canceled), errs.StatusClientClosedRequest, errs.CodeClientClosedRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			router.GET("/context", func(c *gin.Context) {
+				errs.ResponseError(c, tt.err)
+			})
+
+			w := performRequest(router, http.MethodGet, "/context", nil)
+			assert.Equal(t, tt.want, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":"`+tt.code.String()+`"`)
+		})
+	}
+}
+
+func TestResponseErrorWithHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/limited", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeTooManyRequests, "",
+			errs.WithHeaders(map[string]string{"X-RateLimit-Remaining": "0"}),
+			errs.WithHeaders(map[string]string{"Retry-After": "30"}),
+		))
+	})
+
+	w := performRequest(router, http.MethodGet, "/limited", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.NotContains(t, w.Body.String(), "Retry-After")
+}
+
+func TestResponseErrorAfterWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	router.GET("/stream", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		errs.ResponseError(c, errs.New(errs.CodeInternalServerError, "Stream failed"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/stream", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+	if entries := l.Entries(); assert.Len(t, entries, 1) {
+		assert.Equal(t, "errs: response already written", entries[0].Message)
+		assert.Equal(t, http.StatusOK, entries[0].Fields["status"])
+	}
+}
+
+func TestSetResponseContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeNotFound, "Benutzer nicht gefunden ü"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	errs.SetResponseContentType("application/problem+json; charset=utf-8")
+	t.Cleanup(func() { errs.SetResponseContentType("") })
+
+	w = performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, "application/problem+json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/users/1", nil), errs.New(errs.CodeNotFound, ""))
+	assert.Equal(t, "application/problem+json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func performRequestWithHeader(router *gin.Engine, method, path, key, value string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set(key, value)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSetPretty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := errs.New(errs.CodeNotFound, "User not found", errs.WithTimestamp(ts))
+	router.GET("/users/1", func(c *gin.Context) {
+		errs.ResponseError(c, err)
+	})
+
+	compact := `{"code":"NOT_FOUND","message":"User not found","timestamp":"2023-06-01T12:00:00Z"}`
+	indented := "{\n    \"code\": \"NOT_FOUND\",\n    \"message\": \"User not found\",\n    \"timestamp\": \"2023-06-01T12:00:00Z\"\n}"
+
+	w := performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, compact, w.Body.String())
+
+	errs.SetPretty(true)
+	t.Cleanup(func() { errs.SetPretty(false) })
+
+	w = performRequest(router, http.MethodGet, "/users/1", nil)
+	assert.Equal(t, indented, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/users/1", nil), err)
+	assert.Equal(t, indented, w.Body.String())
+}
+
+func TestSetExposeInfo(t *testing.T) {
+	l := &errs.TestLogger{}
+	errs.SetLogger(l)
+	t.Cleanup(func() { errs.SetLogger(nil) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/orders/7", func(c *gin.Context) {
+		errs.ResponseError(c, errs.New(errs.CodeConflict, "Order already shipped",
+			errs.WithInfo(map[string]interface{}{"table": "orders"}),
+			errs.WithData(map[string]interface{}{"status": "shipped"}),
+			errs.WithLogErr(errors.New("update rejected")),
+		))
+	})
+
+	w := performRequest(router, http.MethodGet, "/orders/7", nil)
+	assert.Contains(t, w.Body.String(), `"info":{"table":"orders"}`)
+	assert.Contains(t, w.Body.String(), `"data":{"status":"shipped"}`)
+
+	errs.SetExposeInfo(false)
+	t.Cleanup(func() { errs.SetExposeInfo(true) })
+
+	w = performRequest(router, http.MethodGet, "/orders/7", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.NotContains(t, w.Body.String(), `"info"`)
+	assert.NotContains(t, w.Body.String(), `"data"`)
+	assert.Contains(t, w.Body.String(), `"message":"Order already shipped"`)
+
+	if entry := lastEntry(l); assert.NotNil(t, entry) {
+		assert.Equal(t, map[string]interface{}{"table": "orders"}, entry.Fields["info"])
+	}
+
+	w = httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/orders/7", nil),
+		errs.New(errs.CodeConflict, "Order already shipped", errs.WithInfo(map[string]interface{}{"table": "orders"})))
+	assert.NotContains(t, w.Body.String(), `"info"`)
+}
+
+func TestSetDevMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/profile", func(c *gin.Context) {
+		inner := errs.Wrap(sql.ErrNoRows, errs.CodeNotFound, "user 42")
+		errs.ResponseError(c, errs.Wrap(fmt.Errorf("load profile: %w", inner), errs.CodeInternalServerError, "Profile unavailable"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/profile", nil)
+	assert.NotContains(t, w.Body.String(), `"causes"`)
+
+	errs.SetDevMode(true)
+	t.Cleanup(func() { errs.SetDevMode(false) })
+
+	w = performRequest(router, http.MethodGet, "/profile", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"info":{"causes":[`+
+		`{"message":"load profile: [NOT_FOUND] user 42"},`+
+		`{"code":"NOT_FOUND","message":"user 42"},`+
+		`{"message":"sql: no rows in result set"}]}`)
+
+	errs.SetExposeInfo(false)
+	t.Cleanup(func() { errs.SetExposeInfo(true) })
+
+	w = performRequest(router, http.MethodGet, "/profile", nil)
+	assert.NotContains(t, w.Body.String(), `"causes"`)
+}
+
+func TestSetDevModeCycle(t *testing.T) {
+	errs.SetDevMode(true)
+	t.Cleanup(func() { errs.SetDevMode(false) })
+
+	cycle := &cycleError{}
+	err := errs.Wrap(cycle, errs.CodeInternalServerError, "")
+	cycle.next = err
+
+	w := httptest.NewRecorder()
+	errs.WriteError(w, httptest.NewRequest(http.MethodGet, "/", nil), err)
+	assert.Contains(t, w.Body.String(), `"causes":[{"message":"cycle"}]`)
+}
+
+func TestSetFallbackMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/boom", func(c *gin.Context) {
+		c.Set("requestID", "req-42")
+		errs.ResponseError(c, errors.New("boom"))
+	})
+
+	w := performRequest(router, http.MethodGet, "/boom", nil)
+	assert.JSONEq(t, `"Internal Server Error"`, w.Body.String())
+
+	errs.SetFallbackMessage("An unexpected error occurred, please contact support with ID {requestID}")
+	t.Cleanup(func() { errs.SetFallbackMessage("") })
+
+	w = performRequest(router, http.MethodGet, "/boom", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `"An unexpected error occurred, please contact support with ID req-42"`, w.Body.String())
+
+	w = performRequestWithHeader(router, http.MethodGet, "/boom", "Accept", "text/plain")
+	assert.Equal(t, "An unexpected error occurred, please contact support with ID req-42", w.Body.String())
+
+	r := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	r = r.WithContext(errs.ContextWithRequestID(r.Context(), "req-7"))
+	w = httptest.NewRecorder()
+	errs.WriteError(w, r, errors.New("boom"))
+	assert.JSONEq(t, `"An unexpected error occurred, please contact support with ID req-7"`, w.Body.String())
+}
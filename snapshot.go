@@ -0,0 +1,48 @@
+package errs
+
+import "github.com/gin-gonic/gin"
+
+// snapshotHeaders are the request headers recorded by WithRequestSnapshot.
+// Credentials such as Authorization and Cookie are never recorded.
+var snapshotHeaders = []string{
+	"Accept",
+	"Content-Length",
+	"Content-Type",
+	"Referer",
+	"User-Agent",
+	"X-Forwarded-For",
+	"X-Request-ID",
+}
+
+// WithRequestSnapshot records the method, path, query and a safe set of
+// headers of the gin request into info.request, to give logs of server
+// errors immediate context. Values of headers registered with
+// RegisterRedactedKeys are redacted.
+func WithRequestSnapshot(c *gin.Context) Option {
+	return func(o *option) {
+		if c == nil || c.Request == nil {
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range snapshotHeaders {
+			v := c.GetHeader(name)
+			if v == "" {
+				continue
+			}
+
+			if isRedacted(name) {
+				v = redacted
+			}
+			headers[name] = v
+		}
+
+		o.setInfo("request", map[string]interface{}{
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+			"route":   c.FullPath(),
+			"query":   c.Request.URL.RawQuery,
+			"headers": headers,
+		})
+	}
+}
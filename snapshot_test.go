@@ -0,0 +1,43 @@
+package errs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestWithRequestSnapshot(t *testing.T) {
+	t.Cleanup(errs.ResetRedactedKeys)
+	errs.RegisterRedactedKeys("X-Forwarded-For")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var err *errs.Error
+	router.POST("/orders/:id", func(c *gin.Context) {
+		err = errs.New(errs.CodeInternalServerError, "", errs.WithRequestSnapshot(c))
+		errs.ResponseError(c, err)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/42?dry_run=true", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("User-Agent", "checkout/1.0")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, map[string]interface{}{
+		"method": http.MethodPost,
+		"path":   "/orders/42",
+		"route":  "/orders/:id",
+		"query":  "dry_run=true",
+		"headers": map[string]string{
+			"User-Agent":      "checkout/1.0",
+			"X-Forwarded-For": "[REDACTED]",
+		},
+	}, err.Info["request"])
+}
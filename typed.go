@@ -0,0 +1,16 @@
+package errs
+
+// WithTyped sets the info key to the typed value, to be read back with
+// GetTyped. Options are applied in order, so use it after WithInfo.
+func WithTyped[T any](key string, v T) Option {
+	return func(o *option) {
+		o.setInfo(key, v)
+	}
+}
+
+// GetTyped returns the info value of the key as a T. It returns the zero
+// value and false if the key is missing or the value is not a T.
+func GetTyped[T any](e *Error, key string) (T, bool) {
+	v, ok := e.Info[key].(T)
+	return v, ok
+}
@@ -0,0 +1,59 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+type quota struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+}
+
+func TestGetTyped(t *testing.T) {
+	err := errs.New(errs.CodeTooManyRequests, "Quota exceeded",
+		errs.WithInfo(map[string]interface{}{"plan": "free"}),
+		errs.WithTyped("quota", quota{Limit: 100, Remaining: 0}),
+	)
+
+	q, ok := errs.GetTyped[quota](err, "quota")
+	assert.True(t, ok)
+	assert.Equal(t, quota{Limit: 100, Remaining: 0}, q)
+
+	plan, ok := errs.GetTyped[string](err, "plan")
+	assert.True(t, ok)
+	assert.Equal(t, "free", plan)
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"quota":{"limit":100,"remaining":0}`)
+}
+
+func TestGetTypedMissingKey(t *testing.T) {
+	err := errs.New(errs.CodeTooManyRequests, "Quota exceeded")
+
+	q, ok := errs.GetTyped[quota](err, "quota")
+	assert.False(t, ok)
+	assert.Equal(t, quota{}, q)
+}
+
+func TestGetTypedTypeMismatch(t *testing.T) {
+	err := errs.New(errs.CodeTooManyRequests, "Quota exceeded", errs.WithTyped("quota", 100))
+
+	q, ok := errs.GetTyped[quota](err, "quota")
+	assert.False(t, ok)
+	assert.Equal(t, quota{}, q)
+
+	n, ok := errs.GetTyped[int](err, "quota")
+	assert.True(t, ok)
+	assert.Equal(t, 100, n)
+}
+
+func TestWithTypedDoesNotModifyInfo(t *testing.T) {
+	info := map[string]interface{}{"plan": "free"}
+	errs.New(errs.CodeTooManyRequests, "", errs.WithInfo(info), errs.WithTyped("limit", 100))
+	assert.Equal(t, map[string]interface{}{"plan": "free"}, info)
+}
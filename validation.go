@@ -0,0 +1,393 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/iancoleman/strcase"
+)
+
+// validationMessages holds the registered message builders keyed by tag.
+var (
+	validationMessagesMu sync.RWMutex
+	validationMessages   = make(map[string]func(validator.FieldError) string)
+)
+
+// FieldError represents a single field that failed validation.
+type FieldError struct {
+	// Field is the name of the field.
+	Field string `json:"field" xml:"name"`
+
+	// Tag is the validation tag that failed, e.g. "max".
+	Tag string `json:"tag" xml:"tag"`
+
+	// Param is the parameter of the tag, e.g. "10" for "max=10".
+	Param string `json:"param,omitempty" xml:"param,omitempty"`
+
+	// Message is the validation message.
+	Message string `json:"message" xml:"message"`
+
+	// Value is the rejected value, set with WithRejectedValues. Values of
+	// redacted keys are replaced with "[REDACTED]".
+	Value interface{} `json:"value,omitempty" xml:"value,omitempty"`
+}
+
+// InvalidStructError returns a new CodeBadRequest error for an invalid
+// struct, or an error with the code set with WithValidationCode. The options
+// are applied before the validation info, which wins on conflicting keys. The
+// validation error is kept as the cause, so errors.As reaches the
+// validator.ValidationErrors.
+func InvalidStructError(err error, opts ...Option) *Error {
+	return New(CodeBadRequest, "", append(opts, withValidation(err))...)
+}
+
+// WithValidationCode sets the code of the error returned by
+// InvalidStructError, CodeBadRequest by default, e.g.
+// CodeUnprocessableEntity for APIs that return 422 on validation failures.
+// The message is the default message of the code. It has no effect on errors
+// that are not validation errors.
+func WithValidationCode(code Code) Option {
+	return func(o *option) {
+		o.validationCode = code
+	}
+}
+
+// ValidationError returns a new CodeValidationFailed error (422) for an
+// invalid struct, with the info and fields of InvalidStructError. It lets
+// clients tell failed validation rules apart from malformed requests, which
+// are CodeBadRequest.
+func ValidationError(err error, opts ...Option) *Error {
+	opts = append(opts, withValidation(err))
+	return New(CodeValidationFailed, "", opts...)
+}
+
+// WithRejectedValues includes the rejected value of each field in the
+// fields of InvalidStructError and ValidationError.
+func WithRejectedValues() Option {
+	return func(o *option) {
+		o.rejectedValues = true
+	}
+}
+
+// withValidation sets the info and fields of the validation error, and the
+// validation error as the cause unless one is set with WithCause.
+func withValidation(err error) Option {
+	return func(o *option) {
+		info := validationInfo(err)
+		o.validationMsgs = make(map[string]interface{}, len(info))
+		for k, v := range info {
+			o.validationMsgs[k] = v
+		}
+
+		for k, v := range o.info {
+			if _, ok := info[k]; !ok {
+				info[k] = v
+			}
+		}
+
+		o.info = info
+		o.validation = true
+		o.fields = fieldErrors(err, o.rejectedValues)
+		if o.cause == nil {
+			o.cause = err
+		}
+	}
+}
+
+// validationInfo returns the validation info for the error. When a field
+// fails several rules, the last message wins unless SetFirstMessagePerField
+// or SetAllMessagesPerField is enabled.
+func validationInfo(err error) map[string]interface{} {
+	result := make(map[string]interface{})
+	if errCast, ok := err.(validator.ValidationErrors); ok {
+		conf := currentConfig()
+		priorities := make(map[string]int, len(errCast))
+		for _, e := range errCast {
+			field := fieldKey(e)
+			p, seen := priorities[field]
+			switch {
+			case seen && conf.firstMessagePerField:
+				if p <= tagPriority(e.Tag()) {
+					continue
+				}
+			case seen && conf.allMessagesPerField:
+				result[field] = appendMessage(result[field], toMessage(e))
+				continue
+			}
+
+			priorities[field] = tagPriority(e.Tag())
+			result[field] = toMessage(e)
+		}
+
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		result[typeErr.Field] = typeMessage(typeErr)
+	case isMalformedJSON(err):
+		result["error"] = malformedJSONMessage
+	default:
+		result["error"] = err.Error()
+	}
+
+	return result
+}
+
+// malformedJSONMessage is the message of JSON bodies that cannot be parsed.
+const malformedJSONMessage = "malformed or empty JSON body"
+
+// isMalformedJSON reports whether err is a JSON syntax error or an empty or
+// truncated body.
+func isMalformedJSON(err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// typeMessage returns the message for a JSON value of the wrong type, such
+// as "age must be a number".
+func typeMessage(e *json.UnmarshalTypeError) string {
+	typ := jsonType(e.Type)
+	if strings.IndexByte("aeiou", typ[0]) >= 0 {
+		return fmt.Sprintf("%s must be an %s", e.Field, typ)
+	}
+
+	return fmt.Sprintf("%s must be a %s", e.Field, typ)
+}
+
+// jsonType returns the JSON type of the values decoded into t.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
+// typeTags are the validation tags that check the type or format of a value.
+var typeTags = map[string]bool{
+	"boolean":  true,
+	"datetime": true,
+	"email":    true,
+	"json":     true,
+	"number":   true,
+	"numeric":  true,
+	"url":      true,
+	"uuid":     true,
+}
+
+// tagPriority returns the priority of the validation tag, lower is more
+// important: required first, then type checks, then everything else.
+func tagPriority(tag string) int {
+	switch {
+	case strings.HasPrefix(tag, "required"):
+		return 0
+	case typeTags[tag]:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fieldErrors returns the validation failures in the order reported by the
+// validator, with the rejected values if values is set. It returns nil if err
+// is not a validator.ValidationErrors.
+func fieldErrors(err error, values bool) []FieldError {
+	errCast, ok := err.(validator.ValidationErrors)
+	if !ok {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return []FieldError{{
+				Field:   typeErr.Field,
+				Tag:     "type",
+				Param:   jsonType(typeErr.Type),
+				Message: typeMessage(typeErr),
+			}}
+		}
+
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(errCast))
+	for _, e := range errCast {
+		field := FieldError{
+			Field:   fieldKey(e),
+			Tag:     e.Tag(),
+			Param:   e.Param(),
+			Message: toMessage(e),
+		}
+
+		if values {
+			field.Value = e.Value()
+			if isRedactedField(field.Field) {
+				field.Value = redacted
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// isRedactedField reports whether any segment of the field key, such as
+// "password" in "credentials.password" or "items[0].password", is registered
+// as redacted, so nested secrets are redacted too.
+func isRedactedField(key string) bool {
+	for _, part := range strings.Split(key, ".") {
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			part = part[:i]
+		}
+
+		if isRedacted(part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendMessage appends msg to the message or messages of a field.
+func appendMessage(v interface{}, msg string) []string {
+	if msgs, ok := v.([]string); ok {
+		return append(msgs, msg)
+	}
+
+	return []string{v.(string), msg}
+}
+
+// fieldKey returns the location of the field in the validated value, such
+// as "address.zipCode" or "items[2].sku", built from the namespace without
+// the name of the top-level struct. Dots in map keys are kept.
+func fieldKey(e validator.FieldError) string {
+	ns := e.Namespace()
+	i := strings.IndexByte(ns, '.')
+	if i < 0 {
+		return fieldName(e.Field())
+	}
+
+	var parts []string
+	depth, start := 0, i+1
+	for j := start; j <= len(ns); j++ {
+		switch {
+		case j == len(ns) || ns[j] == '.' && depth == 0:
+			parts = append(parts, fieldName(ns[start:j]))
+			start = j + 1
+		case ns[j] == '[':
+			depth++
+		case ns[j] == ']':
+			depth--
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// fieldName returns the name of the field converted with the field name
+// transformer, lower camel case by default. Map keys and slice indexes such
+// as "Prices[sku1]" are kept verbatim, e.g. "prices[sku1]".
+func fieldName(field string) string {
+	transform := currentConfig().fieldName
+	if transform == nil {
+		transform = strcase.ToLowerCamel
+	}
+
+	if i := strings.IndexByte(field, '['); i >= 0 {
+		return transform(field[:i]) + field[i:]
+	}
+
+	return transform(field)
+}
+
+// RegisterValidationMessage registers fn as the message builder for the
+// validation tag. It overrides the built-in message and any previously
+// registered message for the same tag. A nil fn removes the registration.
+func RegisterValidationMessage(tag string, fn func(validator.FieldError) string) {
+	validationMessagesMu.Lock()
+	defer validationMessagesMu.Unlock()
+
+	if fn == nil {
+		delete(validationMessages, tag)
+		return
+	}
+
+	validationMessages[tag] = fn
+}
+
+// validationMessage returns the registered message builder for the tag.
+func validationMessage(tag string) (func(validator.FieldError) string, bool) {
+	validationMessagesMu.RLock()
+	defer validationMessagesMu.RUnlock()
+
+	fn, ok := validationMessages[tag]
+	return fn, ok
+}
+
+// toMessage returns the message for the validation error.
+func toMessage(e validator.FieldError) string {
+	if fn, ok := validationMessage(e.Tag()); ok {
+		return fn(e)
+	}
+
+	field := fieldName(e.Field())
+	switch e.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "max":
+		switch e.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s cannot be longer than %s", field, plural(e.Param(), "character"))
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s cannot contain more than %s", field, plural(e.Param(), "item"))
+		}
+		return fmt.Sprintf("%s must be at most %s", field, e.Param())
+	case "min":
+		switch e.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be at least %s long", field, plural(e.Param(), "character"))
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must contain at least %s", field, plural(e.Param(), "item"))
+		}
+		return fmt.Sprintf("%s must be at least %s", field, e.Param())
+	case "email":
+		return "invalid email format"
+	case "len":
+		return fmt.Sprintf("%s must be %s characters long", field, e.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be %s", field, e.Param())
+	}
+
+	return fmt.Sprintf("%s is not valid", field)
+}
+
+// plural returns the count followed by the noun, pluralized unless the
+// count is 1.
+func plural(count, noun string) string {
+	if count == "1" {
+		return count + " " + noun
+	}
+
+	return count + " " + noun + "s"
+}
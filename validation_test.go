@@ -0,0 +1,344 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/iancoleman/strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestRegisterValidationMessage(t *testing.T) {
+	type test struct {
+		Code string `validate:"startswith=TH"`
+	}
+
+	errs.RegisterValidationMessage("startswith", func(e validator.FieldError) string {
+		return fmt.Sprintf("%s must start with %s", e.Field(), e.Param())
+	})
+	t.Cleanup(func() { errs.RegisterValidationMessage("startswith", nil) })
+
+	e := errs.InvalidStructError(validator.New().Struct(test{Code: "US"}))
+	assert.Equal(t, "Code must start with TH", e.Info["code"])
+}
+
+func TestRegisterValidationMessageOverridesBuiltIn(t *testing.T) {
+	type test struct {
+		Name string `validate:"required"`
+	}
+
+	errs.RegisterValidationMessage("required", func(e validator.FieldError) string {
+		return "please fill in " + e.Field()
+	})
+	t.Cleanup(func() { errs.RegisterValidationMessage("required", nil) })
+
+	e := errs.InvalidStructError(validator.New().Struct(test{}))
+	assert.Equal(t, "please fill in Name", e.Info["name"])
+
+	errs.RegisterValidationMessage("required", nil)
+	e = errs.InvalidStructError(validator.New().Struct(test{}))
+	assert.Equal(t, "name is required", e.Info["name"])
+}
+
+func TestInvalidStructErrorFieldsOrder(t *testing.T) {
+	type test struct {
+		Name  string `validate:"required"`
+		Email string `validate:"email"`
+		Age   int    `validate:"min=18"`
+		Role  string `validate:"oneof=admin user"`
+	}
+
+	for i := 0; i < 10; i++ {
+		e := errs.InvalidStructError(validator.New().Struct(test{Email: "x", Role: "guest"}))
+		assert.Equal(t, []errs.FieldError{
+			{Field: "name", Tag: "required", Message: "name is required"},
+			{Field: "email", Tag: "email", Message: "invalid email format"},
+			{Field: "age", Tag: "min", Param: "18", Message: "age must be at least 18"},
+			{Field: "role", Tag: "oneof", Param: "admin user", Message: "role must be admin user"},
+		}, e.Fields)
+	}
+}
+
+func TestInvalidStructErrorFieldsWithNonValidationError(t *testing.T) {
+	e := errs.InvalidStructError(errors.New("unexpected EOF"))
+	assert.Nil(t, e.Fields)
+	assert.Equal(t, "unexpected EOF", e.Info["error"])
+}
+
+func TestSetFirstMessagePerField(t *testing.T) {
+	type test struct {
+		Email string
+	}
+
+	v := validator.New()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		sl.ReportError("", "Email", "Email", "max", "5")
+		sl.ReportError("", "Email", "Email", "email", "")
+		sl.ReportError("", "Email", "Email", "required", "")
+		sl.ReportError("", "Email", "Email", "min", "3")
+	}, test{})
+
+	e := errs.InvalidStructError(v.Struct(test{}))
+	assert.Equal(t, "email must be at least 3 characters long", e.Info["email"])
+
+	errs.SetFirstMessagePerField(true)
+	t.Cleanup(func() { errs.SetFirstMessagePerField(false) })
+
+	e = errs.InvalidStructError(v.Struct(test{}))
+	assert.Equal(t, "email is required", e.Info["email"])
+	assert.Len(t, e.Fields, 4)
+}
+
+func TestSetAllMessagesPerField(t *testing.T) {
+	type test struct {
+		Email string
+		Name  string `validate:"required"`
+	}
+
+	v := validator.New()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		sl.ReportError("", "Email", "Email", "email", "")
+		sl.ReportError("", "Email", "Email", "min", "3")
+	}, test{})
+
+	errs.SetAllMessagesPerField(true)
+	t.Cleanup(func() { errs.SetAllMessagesPerField(false) })
+
+	e := errs.InvalidStructError(v.Struct(test{}))
+	assert.Equal(t, []string{"invalid email format", "email must be at least 3 characters long"}, e.Info["email"])
+	assert.Equal(t, "name is required", e.Info["name"])
+
+	errs.SetFirstMessagePerField(true)
+	t.Cleanup(func() { errs.SetFirstMessagePerField(false) })
+
+	e = errs.InvalidStructError(v.Struct(test{}))
+	assert.Equal(t, "invalid email format", e.Info["email"])
+}
+
+func TestInvalidStructErrorFieldsJSON(t *testing.T) {
+	type test struct {
+		Name string `validate:"max=3"`
+	}
+
+	e := errs.InvalidStructError(validator.New().Struct(test{Name: "Alice"}))
+	body, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"fields":[{"field":"name","tag":"max","param":"3","message":"name cannot be longer than 3 characters"}]`)
+}
+
+func TestInvalidStructErrorMinMaxByKind(t *testing.T) {
+	type test struct {
+		Age      int      `validate:"min=18"`
+		Score    float64  `validate:"max=10"`
+		Name     string   `validate:"min=2"`
+		Initial  string   `validate:"min=1"`
+		Tags     []string `validate:"min=1"`
+		Children []string `validate:"max=2"`
+	}
+
+	e := errs.InvalidStructError(validator.New().Struct(test{Age: 16, Score: 12, Name: "A", Children: []string{"a", "b", "c"}}))
+	assert.Equal(t, map[string]interface{}{
+		"age":      "age must be at least 18",
+		"score":    "score must be at most 10",
+		"name":     "name must be at least 2 characters long",
+		"initial":  "initial must be at least 1 character long",
+		"tags":     "tags must contain at least 1 item",
+		"children": "children cannot contain more than 2 items",
+	}, e.Info)
+}
+
+func TestInvalidStructErrorWithRejectedValues(t *testing.T) {
+	t.Cleanup(errs.ResetRedactedKeys)
+	errs.RegisterRedactedKeys("password")
+
+	type test struct {
+		Age      int    `validate:"min=18"`
+		Password string `validate:"min=8"`
+	}
+
+	err := validator.New().Struct(test{Age: 16, Password: "hunter2"})
+
+	e := errs.InvalidStructError(err)
+	assert.Nil(t, e.Fields[0].Value)
+
+	e = errs.InvalidStructError(err, errs.WithRejectedValues(), errs.WithInfo(map[string]interface{}{"form": "signup"}))
+	assert.Equal(t, []errs.FieldError{
+		{Field: "age", Tag: "min", Param: "18", Message: "age must be at least 18", Value: 16},
+		{Field: "password", Tag: "min", Param: "8", Message: "password must be at least 8 characters long", Value: "[REDACTED]"},
+	}, e.Fields)
+	assert.Equal(t, "signup", e.Info["form"])
+	assert.Equal(t, "age must be at least 18", e.Info["age"])
+
+	body, jsonErr := json.Marshal(e)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"value":16`)
+	assert.NotContains(t, string(body), "hunter2")
+
+	var got struct {
+		Info map[string]interface{} `json:"info"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, "password must be at least 8 characters long", got.Info["password"])
+
+	wrapped := errs.Wrap(e, errs.CodeUnprocessableEntity, "Signup rejected")
+	body, jsonErr = json.Marshal(wrapped)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"password":"password must be at least 8 characters long"`)
+
+	body, jsonErr = json.Marshal(errs.InvalidStructError(err).AppendInfo("password", "hunter2"))
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"password":"[REDACTED]"`)
+
+	type credentials struct {
+		Password string `validate:"min=8"`
+	}
+	type account struct {
+		Credentials credentials
+		Users       []credentials `validate:"dive"`
+	}
+
+	err = validator.New().Struct(account{
+		Credentials: credentials{Password: "s3cret"},
+		Users:       []credentials{{Password: "hunter2"}},
+	})
+
+	e = errs.InvalidStructError(err, errs.WithRejectedValues())
+	if assert.Len(t, e.Fields, 2) {
+		assert.Equal(t, "credentials.password", e.Fields[0].Field)
+		assert.Equal(t, "[REDACTED]", e.Fields[0].Value)
+		assert.Equal(t, "users[0].password", e.Fields[1].Field)
+		assert.Equal(t, "[REDACTED]", e.Fields[1].Value)
+	}
+
+	body, jsonErr = json.Marshal(e)
+	assert.NoError(t, jsonErr)
+	assert.NotContains(t, string(body), "s3cret")
+	assert.NotContains(t, string(body), "hunter2")
+}
+
+func TestInvalidStructErrorNamespaces(t *testing.T) {
+	type address struct {
+		ZipCode string `validate:"required"`
+	}
+	type item struct {
+		Sku  string `validate:"required"`
+		Name string `validate:"required"`
+	}
+	type order struct {
+		Name     string            `validate:"required"`
+		Address  address           `validate:"required"`
+		Items    []item            `validate:"dive"`
+		Discount map[string]string `validate:"dive,required"`
+	}
+
+	err := validator.New().Struct(order{
+		Name:     "Alice",
+		Items:    []item{{Sku: "a1", Name: "Apple"}, {}, {Sku: "c3"}},
+		Discount: map[string]string{"black.friday": ""},
+	})
+	e := errs.InvalidStructError(err)
+
+	assert.Equal(t, map[string]interface{}{
+		"address.zipCode":        "zipCode is required",
+		"items[1].sku":           "sku is required",
+		"items[1].name":          "name is required",
+		"items[2].name":          "name is required",
+		"discount[black.friday]": "discount[black.friday] is required",
+	}, e.Info)
+
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f.Field
+	}
+	assert.Equal(t, []string{"address.zipCode", "items[1].sku", "items[1].name", "items[2].name", "discount[black.friday]"}, fields)
+}
+
+func TestSetFieldNameTransformer(t *testing.T) {
+	type address struct {
+		ZipCode string `validate:"required"`
+	}
+	type user struct {
+		FirstName string  `validate:"required"`
+		Address   address `validate:"required"`
+	}
+
+	errs.SetFieldNameTransformer(strcase.ToSnake)
+	t.Cleanup(func() { errs.SetFieldNameTransformer(nil) })
+
+	e := errs.InvalidStructError(validator.New().Struct(user{}))
+	assert.Equal(t, map[string]interface{}{
+		"first_name":       "first_name is required",
+		"address.zip_code": "zip_code is required",
+	}, e.Info)
+
+	errs.SetFieldNameTransformer(nil)
+
+	e = errs.InvalidStructError(validator.New().Struct(user{}))
+	assert.Equal(t, "firstName is required", e.Info["firstName"])
+}
+
+func TestWithValidationCode(t *testing.T) {
+	type user struct {
+		Email string `validate:"required"`
+	}
+	err := validator.New().Struct(user{})
+
+	e := errs.InvalidStructError(err)
+	assert.Equal(t, errs.CodeBadRequest, e.Code)
+	assert.Equal(t, http.StatusBadRequest, e.HTTPStatusCode())
+	assert.Equal(t, "Bad Request", e.Message)
+
+	e = errs.InvalidStructError(err, errs.WithValidationCode(errs.CodeUnprocessableEntity), errs.WithInfo(map[string]interface{}{"form": "signup"}))
+	assert.Equal(t, errs.CodeUnprocessableEntity, e.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.HTTPStatusCode())
+	assert.Equal(t, "Unprocessable Entity", e.Message)
+	assert.Equal(t, map[string]interface{}{"email": "email is required", "form": "signup"}, e.Info)
+	assert.Len(t, e.Fields, 1)
+
+	assert.Equal(t, errs.CodeNotFound, errs.New(errs.CodeNotFound, "", errs.WithValidationCode(errs.CodeConflict)).Code)
+}
+
+func TestValidationError(t *testing.T) {
+	type user struct {
+		Email string `validate:"required,email"`
+	}
+
+	e := errs.ValidationError(validator.New().Struct(user{Email: "alice"}), errs.WithRejectedValues())
+	assert.Equal(t, errs.CodeValidationFailed, e.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.HTTPStatusCode())
+	assert.Equal(t, "Validation Failed", e.Message)
+	assert.Equal(t, "Validation Failed", errs.New(errs.CodeValidationFailed, "").Message)
+	assert.Equal(t, "Validation Failed", errs.ValidationFailed.Message)
+	assert.Equal(t, "Validation Failed", errs.InvalidStructError(validator.New().Struct(user{}), errs.WithValidationCode(errs.CodeValidationFailed)).Message)
+	assert.Equal(t, "invalid email format", e.Info["email"])
+	if assert.Len(t, e.Fields, 1) {
+		assert.Equal(t, "alice", e.Fields[0].Value)
+	}
+
+	assert.Equal(t, errs.CodeBadRequest, errs.InvalidStructError(validator.New().Struct(user{})).Code)
+	assert.Equal(t, errs.CodeUnprocessableEntity, errs.CodeFromStatus(http.StatusUnprocessableEntity))
+}
+
+func TestInvalidStructErrorUnwrap(t *testing.T) {
+	type user struct {
+		Name  string `validate:"required"`
+		Email string `validate:"required,email"`
+	}
+
+	e := errs.InvalidStructError(validator.New().Struct(user{Email: "alice"}))
+
+	var verrs validator.ValidationErrors
+	if assert.True(t, errors.As(e, &verrs)) && assert.Len(t, verrs, 2) {
+		assert.Equal(t, "Name", verrs[0].Field())
+		assert.Equal(t, "email", verrs[1].Tag())
+	}
+	assert.Equal(t, "name is required", e.Info["name"])
+
+	e = errs.ValidationError(validator.New().Struct(user{}))
+	assert.True(t, errors.As(e, &verrs))
+}
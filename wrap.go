@@ -0,0 +1,139 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Wrap returns a new error with err as its cause, like New with WithCause.
+// The cause is reachable with errors.Is and errors.As, including through
+// stdlib wrapping such as fmt.Errorf("...: %w", err). If err wraps an
+// *Error, its info is deep merged into the new error so metadata set near the
+// source is kept. Values of the new error win on conflicting keys, and nested
+// maps present on both sides are merged key by key.
+//
+// Wrapping an *Error with the same code collapses the two instead of nesting
+// another layer: the messages are joined as "outer: inner", the info is
+// merged, and the cause of err becomes the cause of the new error. The
+// collapsed error still matches err with errors.Is. Pass WithNesting to keep
+// every layer.
+func Wrap(err error, code Code, msg string, opts ...Option) *Error {
+	if inner, ok := err.(*Error); ok && inner.Code == code && !applyOptions(context.Background(), opts).nest {
+		return collapse(inner, msg, opts)
+	}
+
+	return New(code, msg, append(opts, WithCause(err))...)
+}
+
+// WithNesting makes Wrap nest an *Error with the same code rather than
+// collapse it.
+func WithNesting() Option {
+	return func(o *option) {
+		o.nest = true
+	}
+}
+
+// collapse returns the error wrapping inner with the same code, merged into a
+// single layer. The options win over the values of inner. The message of an
+// inner error with interpolation is joined as is and interpolated with the
+// merged info.
+func collapse(inner *Error, msg string, opts []Option) *Error {
+	innerMsg := inner.message()
+	if inner.interpolate {
+		innerMsg = inner.baseMessage()
+	}
+
+	if msg == "" {
+		msg = innerMsg
+	} else {
+		msg += ": " + innerMsg
+	}
+
+	merged := make([]Option, 0, len(opts)+2)
+	merged = append(merged, func(o *option) {
+		o.publicMessage = inner.PublicMessage
+		o.fields = inner.Fields
+		o.data = inner.Data
+		o.docsURL = inner.DocsURL
+		o.headers = inner.headers
+		o.severity = inner.Severity
+		o.status = inner.status
+		o.validationMsgs = inner.validationMessages
+	})
+	merged = append(merged, opts...)
+	merged = append(merged, func(o *option) {
+		o.info = mergeInfo(o.info, inner.Info)
+		o.cause = inner.cause
+		o.interpolate = o.interpolate || inner.interpolate
+		if inner.lazyInfo != nil {
+			o.infoFunc = mergeInfoFuncs(o.infoFunc, inner.lazyInfo.get)
+		}
+	})
+
+	e := New(inner.Code, msg, merged...)
+	e.collapsed = append([]*Error{inner}, inner.collapsed...)
+	return e
+}
+
+// mergeInfoFuncs returns an info function merging the info of outer, when
+// set, with the info of inner. Outer values win on conflicting keys.
+func mergeInfoFuncs(outer, inner func() map[string]interface{}) func() map[string]interface{} {
+	if outer == nil {
+		return inner
+	}
+
+	return func() map[string]interface{} {
+		return mergeInfo(outer(), inner())
+	}
+}
+
+// Is reports whether target is one of the errors collapsed into e by Wrap.
+func (e *Error) Is(target error) bool {
+	for _, c := range e.collapsed {
+		if c == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Wrapf returns a new error like Wrap with a message formatted according to
+// the format specifier.
+func Wrapf(err error, code Code, format string, args ...interface{}) *Error {
+	return Wrap(err, code, fmt.Sprintf(format, args...))
+}
+
+// Depth returns the number of errors wrapped below e, walking the chain of
+// Unwrap through both *Error causes and stdlib wrapping. An error without a
+// cause has depth 0. The walk stops when an error repeats, so a cycle is
+// counted once.
+func (e *Error) Depth() int {
+	depth := 0
+	walkChain(e, func(error) bool {
+		depth++
+		return true
+	})
+
+	return depth
+}
+
+// walkChain calls fn for each error wrapped below err, walking the chain of
+// Unwrap, until fn returns false. The walk stops when an error repeats.
+func walkChain(err error, fn func(error) bool) {
+	seen := map[error]struct{}{err: {}}
+	for err := errors.Unwrap(err); err != nil; err = errors.Unwrap(err) {
+		if reflect.TypeOf(err).Comparable() {
+			if _, ok := seen[err]; ok {
+				return
+			}
+			seen[err] = struct{}{}
+		}
+
+		if !fn(err) {
+			return
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package errs_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirathawat/errs"
+)
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := errs.Wrap(cause, errs.CodeServiceUnavailable, "Payments unavailable", errs.WithInfo(map[string]interface{}{"provider": "stripe"}))
+
+	assert.Equal(t, errs.CodeServiceUnavailable, err.Code)
+	assert.Equal(t, "Payments unavailable", err.Message)
+	assert.Equal(t, "stripe", err.Info["provider"])
+	assert.Same(t, cause, errors.Unwrap(err))
+}
+
+func TestWrapf(t *testing.T) {
+	err := errs.Wrapf(sql.ErrNoRows, errs.CodeNotFound, "user %d not found", 42)
+
+	assert.Equal(t, "[NOT_FOUND] user 42 not found", err.Error())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestWrapMixedWithStdlib(t *testing.T) {
+	// stdlib → errs → stdlib → errs
+	inner := fmt.Errorf("query users: %w", sql.ErrNoRows)
+	notFound := errs.Wrap(inner, errs.CodeNotFound, "User not found")
+	outer := fmt.Errorf("load profile: %w", notFound)
+	top := errs.Wrapf(outer, errs.CodeInternalServerError, "render %s", "profile")
+
+	assert.ErrorIs(t, top, sql.ErrNoRows)
+	assert.ErrorIs(t, top, notFound)
+
+	var e *errs.Error
+	if assert.True(t, errors.As(outer, &e)) {
+		assert.Same(t, notFound, e)
+	}
+
+	if assert.True(t, errors.As(top, &e)) {
+		assert.Same(t, top, e)
+	}
+
+	var unwrapped []error
+	for err := error(top); err != nil; err = errors.Unwrap(err) {
+		unwrapped = append(unwrapped, err)
+	}
+	assert.Equal(t, []error{top, outer, notFound, inner, sql.ErrNoRows}, unwrapped)
+}
+
+func TestWrapMergesInfo(t *testing.T) {
+	inner := errs.New(errs.CodeNotFound, "User not found", errs.WithInfo(map[string]interface{}{
+		"userId": 42,
+		"source": "db",
+		"query":  map[string]interface{}{"table": "users", "limit": 1},
+	}))
+	outer := errs.Wrap(fmt.Errorf("load profile: %w", inner), errs.CodeInternalServerError, "Profile unavailable",
+		errs.WithInfo(map[string]interface{}{
+			"source": "profile",
+			"query":  map[string]interface{}{"limit": 10},
+		}),
+	)
+
+	assert.Equal(t, map[string]interface{}{
+		"userId": 42,
+		"source": "profile",
+		"query":  map[string]interface{}{"table": "users", "limit": 10},
+	}, outer.Info)
+	assert.Equal(t, "db", inner.Info["source"])
+	assert.Equal(t, map[string]interface{}{"table": "users", "limit": 1}, inner.Info["query"])
+}
+
+func TestWithCauseMergesInfo(t *testing.T) {
+	inner := errs.New(errs.CodeNotFound, "", errs.WithInfo(map[string]interface{}{"userId": 42}))
+	outer := errs.New(errs.CodeInternalServerError, "", errs.WithCause(inner))
+
+	assert.Equal(t, map[string]interface{}{"userId": 42}, outer.Info)
+}
+
+type cycleError struct {
+	next error
+}
+
+func (e *cycleError) Error() string { return "cycle" }
+func (e *cycleError) Unwrap() error { return e.next }
+
+func TestDepth(t *testing.T) {
+	leaf := errs.New(errs.CodeNotFound, "User not found")
+	assert.Equal(t, 0, leaf.Depth())
+
+	assert.Equal(t, 1, errs.Wrap(sql.ErrNoRows, errs.CodeNotFound, "").Depth())
+
+	err := errs.Wrap(fmt.Errorf("load profile: %w", errs.Wrap(sql.ErrNoRows, errs.CodeNotFound, "")), errs.CodeInternalServerError, "")
+	assert.Equal(t, 3, err.Depth())
+
+	cycle := &cycleError{}
+	err = errs.Wrap(cycle, errs.CodeInternalServerError, "")
+	cycle.next = err
+	assert.Equal(t, 1, err.Depth())
+}
+
+func TestHTTPStatusCodeWrappedCode(t *testing.T) {
+	inner := errs.Wrap(sql.ErrNoRows, errs.CodeNotFound, "User not found")
+
+	err := errs.Wrap(fmt.Errorf("load profile: %w", inner), "PROFILE_FAILED", "")
+	assert.Equal(t, http.StatusNotFound, err.HTTPStatusCode())
+
+	err = errs.Wrap(inner, errs.CodeConflict, "")
+	assert.Equal(t, http.StatusConflict, err.HTTPStatusCode())
+
+	err = errs.Wrap(errors.New("boom"), "PROFILE_FAILED", "")
+	assert.Equal(t, http.StatusInternalServerError, err.HTTPStatusCode())
+
+	cycle := &cycleError{}
+	err = errs.Wrap(cycle, "PROFILE_FAILED", "")
+	cycle.next = err
+	assert.Equal(t, http.StatusInternalServerError, err.HTTPStatusCode())
+}
+
+func TestAsError(t *testing.T) {
+	notFound := errs.New(errs.CodeNotFound, "User not found")
+
+	e, ok := errs.AsError(notFound)
+	assert.True(t, ok)
+	assert.Same(t, notFound, e)
+
+	e, ok = errs.AsError(fmt.Errorf("load profile: %w", notFound))
+	assert.True(t, ok)
+	assert.Same(t, notFound, e)
+
+	e, ok = errs.AsError(sql.ErrNoRows)
+	assert.False(t, ok)
+	assert.Nil(t, e)
+
+	e, ok = errs.AsError(nil)
+	assert.False(t, ok)
+	assert.Nil(t, e)
+}
+
+func TestStatusCode(t *testing.T) {
+	notFound := errs.New(errs.CodeNotFound, "User not found")
+
+	assert.Equal(t, http.StatusNotFound, errs.StatusCode(notFound))
+	assert.Equal(t, http.StatusNotFound, errs.StatusCode(fmt.Errorf("load profile: %w", notFound)))
+	assert.Equal(t, http.StatusConflict, errs.StatusCode(errs.Wrap(notFound, errs.CodeConflict, "")))
+	assert.Equal(t, http.StatusInternalServerError, errs.StatusCode(errors.New("boom")))
+	assert.Equal(t, http.StatusInternalServerError, errs.StatusCode(nil))
+}
+
+func TestWrapCollapsesSameCode(t *testing.T) {
+	inner := errs.Wrap(sql.ErrNoRows, errs.CodeNotFound, "user 42", errs.WithInfo(map[string]interface{}{
+		"userId": 42,
+		"source": "db",
+	}))
+	err := errs.Wrap(inner, errs.CodeNotFound, "load profile", errs.WithInfo(map[string]interface{}{
+		"source": "profile",
+	}))
+
+	assert.Equal(t, "load profile: user 42", err.Message)
+	assert.Equal(t, map[string]interface{}{"userId": 42, "source": "profile"}, err.Info)
+	assert.Equal(t, 1, err.Depth())
+	assert.Same(t, sql.ErrNoRows, errors.Unwrap(err))
+	assert.ErrorIs(t, err, inner)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = errs.Wrap(errs.NotFound, errs.CodeNotFound, "")
+	assert.Equal(t, "Not Found", err.Message)
+	assert.Equal(t, 0, err.Depth())
+	assert.ErrorIs(t, err, errs.NotFound)
+}
+
+func TestWrapCollapseKeepsInnerState(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTeapot,
+		Body:       io.NopCloser(strings.NewReader(`{"code":"TEAPOT","message":"short and stout"}`)),
+	}
+	var teapot *errs.Error
+	assert.True(t, errors.As(errs.CheckResponse(resp), &teapot))
+
+	err := errs.Wrap(teapot, "TEAPOT", "brew")
+	assert.Equal(t, "brew: short and stout", err.Message)
+	assert.Equal(t, http.StatusTeapot, err.HTTPStatusCode())
+	assert.Equal(t, http.StatusTeapot, errs.Wrap(teapot, "TEAPOT", "brew", errs.WithNesting()).HTTPStatusCode())
+
+	inner := errs.New(errs.CodeNotFound, "user {userId}",
+		errs.WithSeverity(errs.SeverityError),
+		errs.WithInterpolation(),
+		errs.WithInfoFunc(func() map[string]interface{} { return map[string]interface{}{"userId": 42} }),
+	)
+	err = errs.Wrap(inner, errs.CodeNotFound, "load profile")
+	assert.Equal(t, errs.SeverityError, err.Severity)
+	assert.Equal(t, "[NOT_FOUND] load profile: user 42", err.Error())
+	assert.Equal(t, errs.SeverityInfo, errs.Wrap(inner, errs.CodeNotFound, "", errs.WithSeverity(errs.SeverityInfo)).Severity)
+
+	body, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.Contains(t, string(body), `"info":{"userId":42}`)
+}
+
+func TestWrapWithNesting(t *testing.T) {
+	inner := errs.Wrap(sql.ErrNoRows, errs.CodeNotFound, "user 42")
+	err := errs.Wrap(inner, errs.CodeNotFound, "load profile", errs.WithNesting())
+
+	assert.Equal(t, "load profile", err.Message)
+	assert.Equal(t, 2, err.Depth())
+	assert.Same(t, inner, errors.Unwrap(err))
+}
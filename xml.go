@@ -0,0 +1,115 @@
+package errs
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// MarshalXML implements xml.Marshaler. The error is written as an <error>
+// element, and each info key as an <entry key="..."> element of <info>.
+// Like MarshalJSON, it uses the public message and redacts registered keys.
+func (e *Error) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	type plain Error
+	p := plain(*e)
+	p.Message = e.publicMessage()
+	p.DocsURL = e.docsURL()
+
+	v := struct {
+		XMLName xml.Name `xml:"error"`
+		*plain
+		Info   xmlInfo    `xml:"info,omitempty"`
+		Fields *xmlFields `xml:"fields,omitempty"`
+	}{
+		plain: &p,
+		Info:  e.redactedInfo(),
+	}
+
+	if len(e.Fields) > 0 {
+		v.Fields = &xmlFields{Fields: e.Fields}
+	}
+
+	return enc.Encode(v)
+}
+
+// xmlFields writes the validation failures as a list of <field> elements.
+type xmlFields struct {
+	Fields []FieldError `xml:"field"`
+}
+
+// xmlInfo writes the info map as a list of <entry> elements.
+type xmlInfo map[string]interface{}
+
+// MarshalXML implements xml.Marshaler. Entries are sorted by key, nested
+// maps are written as nested entries and errors as <error> elements.
+func (info xmlInfo) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if len(info) == 0 {
+		return nil
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(info))
+	for k := range info {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		entry := xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: k}},
+		}
+
+		var err error
+		switch v := info[k].(type) {
+		case map[string]interface{}:
+			err = xmlInfo(v).MarshalXML(enc, entry)
+		case *Error:
+			err = xmlErrors{v}.MarshalXML(enc, entry)
+		case []*Error:
+			err = xmlErrors(v).MarshalXML(enc, entry)
+		default:
+			err = enc.EncodeElement(fmt.Sprint(v), entry)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlErrors writes errors as a list of <error> elements.
+type xmlErrors []*Error
+
+// MarshalXML implements xml.Marshaler.
+func (es xmlErrors) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, e := range es {
+		if err := e.MarshalXML(enc, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// MarshalXML implements xml.Marshaler by writing the fallback as an <error>
+// element with CodeInternalServerError.
+func (f fallback) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	return enc.Encode(struct {
+		XMLName xml.Name `xml:"error"`
+		Code    Code     `xml:"code"`
+		Message string   `xml:"message"`
+	}{
+		Code:    CodeInternalServerError,
+		Message: string(f),
+	})
+}